@@ -0,0 +1,109 @@
+package hexonet
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// StatusAccount only reports the user of an account, so account IDs can not be verified and are refused
+var accountIDPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// Parsed form of expected_account: user[!role][@live|@ote]
+type expectedAccount struct {
+	User string
+	// nil if no role was given, then the role is not checked
+	Role *string
+	// nil if no system was given, then the system is not checked
+	Live *bool
+}
+
+func parseExpectedAccount(val string) (*expectedAccount, error) {
+	res := &expectedAccount{}
+
+	account, system, hasSystem := strings.Cut(val, "@")
+	if hasSystem {
+		var live bool
+		switch strings.ToLower(system) {
+		case "live":
+			live = true
+		case "ote":
+			live = false
+		default:
+			return nil, fmt.Errorf("invalid system %s in expected_account, must be live or ote", system)
+		}
+		res.Live = &live
+	}
+
+	user, role, hasRole := strings.Cut(account, "!")
+	if user == "" {
+		return nil, fmt.Errorf("expected_account must contain the user (format: user[!role][@live|@ote])")
+	}
+	if accountIDPattern.MatchString(user) {
+		return nil, fmt.Errorf("expected_account must contain the user, not the account ID %s, as the API only reports the user of the logged in account (format: user[!role][@live|@ote])", user)
+	}
+	res.User = user
+	if hasRole {
+		res.Role = &role
+	}
+
+	return res, nil
+}
+
+func systemName(live bool) string {
+	if live {
+		return "live"
+	}
+	return "ote"
+}
+
+func systemURL(live bool) string {
+	if live {
+		return apiclient.ISPAPI_CONNECTION_URL_LIVE
+	}
+	return apiclient.ISPAPI_CONNECTION_URL_OTE
+}
+
+// Checks system and role against the expectation, both are known before logging in
+func (expected *expectedAccount) checkConfig(cfg *ClientConfig, diags *diag.Diagnostics) {
+	if expected.Live != nil && *expected.Live != cfg.Live {
+		diags.AddError("Wrong system", fmt.Sprintf("expected_account requires the %s system, but the provider is configured for %s", systemName(*expected.Live), systemName(cfg.Live)))
+	}
+
+	// The endpoint decides which system is actually reached, whatever live is set to
+	if expected.Live != nil && cfg.EndpointURL != "" {
+		systemURL := systemURL(*expected.Live)
+		if !strings.EqualFold(strings.TrimRight(cfg.EndpointURL, "/"), systemURL) {
+			diags.AddError("Wrong system", fmt.Sprintf("expected_account requires the %s system at %s, but the provider is configured for endpoint %s (leave the system out of expected_account to use other endpoints)", systemName(*expected.Live), systemURL, cfg.EndpointURL))
+		}
+	}
+
+	if expected.Role != nil && !strings.EqualFold(*expected.Role, cfg.Role) {
+		diags.AddError("Wrong account", fmt.Sprintf("expected_account requires role %q, but the provider is configured with role %q", *expected.Role, cfg.Role))
+	}
+}
+
+// Checks the logged in account (queried using StatusAccount) against the expectation
+func (expected *expectedAccount) checkLoggedIn(ctx context.Context, cl Client, diags *diag.Diagnostics) {
+	resp := cl.Request(ctx, map[string]interface{}{
+		"COMMAND": "StatusAccount",
+	})
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	if diags.HasError() {
+		return
+	}
+
+	user := utils.ColumnFirstOrDefault(resp, "USER", "").(string)
+	if user == "" {
+		diags.AddError("Could not verify account", "StatusAccount did not return the USER of the logged in account")
+		return
+	}
+	if !strings.EqualFold(user, expected.User) {
+		diags.AddError("Wrong account", fmt.Sprintf("expected_account requires user %q, but the provider is logged in as %q", expected.User, user))
+	}
+}
@@ -0,0 +1,66 @@
+package hexonet
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestExpectedAccountCheckConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  string
+		cfg       ClientConfig
+		expectErr bool
+	}{
+		{"matching system", "user@ote", ClientConfig{Live: false}, false},
+		{"wrong system", "user@live", ClientConfig{Live: false}, true},
+		{"no system", "user", ClientConfig{Live: false, EndpointURL: "https://api.example.com/api/call.cgi"}, false},
+		{"matching role", "user!role", ClientConfig{Role: "ROLE"}, false},
+		{"wrong role", "user!role", ClientConfig{Role: "other"}, true},
+		{"endpoint of the system", "user@ote", ClientConfig{Live: false, EndpointURL: "https://api-ote.ispapi.net/api/call.cgi"}, false},
+		// live only selects the system entity, the endpoint decides which system is reached
+		{"endpoint of the other system", "user@ote", ClientConfig{Live: false, EndpointURL: "https://api.ispapi.net/api/call.cgi"}, true},
+		{"unknown endpoint", "user@live", ClientConfig{Live: true, EndpointURL: "https://api.example.com/api/call.cgi"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := parseExpectedAccount(tt.expected)
+			if err != nil {
+				t.Fatalf("parsing %q failed: %v", tt.expected, err)
+			}
+			diags := diag.Diagnostics{}
+			expected.checkConfig(&tt.cfg, &diags)
+			if diags.HasError() != tt.expectErr {
+				t.Errorf("expected error %t, got %v", tt.expectErr, diags)
+			}
+		})
+	}
+}
+
+func TestParseExpectedAccount(t *testing.T) {
+	tests := []struct {
+		val       string
+		expectErr bool
+	}{
+		{"user", false},
+		{"user!role@live", false},
+		{"user@OTE", false},
+		{"", true},
+		{"!role", true},
+		{"user@staging", true},
+		// Account IDs can not be checked against StatusAccount
+		{"123456", true},
+		{"123456@live", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			_, err := parseExpectedAccount(tt.val)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error %t, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package hexonet
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/responsetemplatemanager"
+)
+
+// Client is the subset of the Hexonet API client the provider relies on
+// Implementations other than the rtldev SDK (fakes, recorders, ...) can be plugged in using NewWithClientFactory
+type Client interface {
+	Request(ctx context.Context, cmd map[string]interface{}) *response.Response
+	Login(params ...string) *response.Response
+	Logout() *response.Response
+	GetSession() (string, error)
+	UseSession(session string)
+}
+
+// ClientConfig contains all settings needed to construct a Client
+type ClientConfig struct {
+	Username        string
+	Role            string
+	Password        string
+	Live            bool
+	HighPerformance bool
+	// Optional overrides, the URL takes precedence over Live and HighPerformance
+	EndpointURL     string
+	ProxyURL        string
+	UserAgentSuffix string
+	// Only set if a custom CA bundle or User-Agent suffix is used, handles all requests to the endpoint (including the proxy)
+	Transport http.RoundTripper
+}
+
+type ClientFactory = func(cfg *ClientConfig) Client
+
+// Client implementation using the rtldev SDK
+type sdkClient struct {
+	*apiclient.APIClient
+	cfg *ClientConfig
+	// Only set if cfg has a custom transport or User-Agent suffix, otherwise the SDK sends the requests
+	httpClient *http.Client
+	userAgent  string
+}
+
+var _ Client = &sdkClient{}
+
+// NewSDKClient wraps an already set up rtldev SDK client, using the credentials (and transport) from cfg
+func NewSDKClient(c *apiclient.APIClient, cfg *ClientConfig) Client {
+	res := &sdkClient{
+		APIClient: c,
+		cfg:       cfg,
+		userAgent: c.GetUserAgent(),
+	}
+	if cfg.UserAgentSuffix != "" {
+		res.userAgent += " " + cfg.UserAgentSuffix
+	}
+	// The SDK can only replace its whole User-Agent, so requests with a suffix are sent by the provider as well
+	if cfg.Transport != nil || cfg.UserAgentSuffix != "" {
+		res.httpClient = &http.Client{
+			Transport: cfg.Transport,
+			Timeout:   sdkSocketTimeout,
+		}
+	}
+	return res
+}
+
+func newSDKClient(cfg *ClientConfig) Client {
+	c := apiclient.NewAPIClient()
+	if cfg.Live {
+		c.UseLIVESystem()
+	} else {
+		c.UseOTESystem()
+	}
+
+	if cfg.HighPerformance {
+		c.UseHighPerformanceConnectionSetup()
+	} else {
+		c.UseDefaultConnectionSetup()
+	}
+
+	if cfg.EndpointURL != "" {
+		c.SetURL(cfg.EndpointURL)
+	}
+
+	// A custom transport already handles the proxy
+	if cfg.Transport == nil && cfg.ProxyURL != "" {
+		c.SetProxy(cfg.ProxyURL)
+	}
+
+	return NewSDKClient(c, cfg)
+}
+
+func (c *sdkClient) Login(params ...string) *response.Response {
+	// The SDK drops the credentials once it has a session, so they need to be set again for every login
+	c.SetRoleCredentials(c.cfg.Username, c.cfg.Role, c.cfg.Password)
+	if c.httpClient == nil {
+		return c.APIClient.Login(params...)
+	}
+
+	otp := ""
+	if len(params) > 0 {
+		otp = params[0]
+	}
+	c.SetOTP(otp)
+
+	resp := c.send(context.Background(), map[string]interface{}{"COMMAND": "StartSession"})
+	if resp.IsSuccess() {
+		session := ""
+		if col := resp.GetColumn("SESSION"); col != nil && len(col.GetData()) > 0 {
+			session = col.GetData()[0]
+		}
+		c.SetSession(session)
+	}
+	return resp
+}
+
+func (c *sdkClient) Logout() *response.Response {
+	if c.httpClient == nil {
+		return c.APIClient.Logout()
+	}
+
+	resp := c.send(context.Background(), map[string]interface{}{"COMMAND": "EndSession"})
+	if resp.IsSuccess() {
+		c.SetSession("")
+	}
+	return resp
+}
+
+func (c *sdkClient) UseSession(session string) {
+	c.SetSession(session)
+}
+
+func (c *sdkClient) Request(ctx context.Context, cmd map[string]interface{}) *response.Response {
+	if ctx.Err() != nil {
+		return makeCancelledResponse(ctx, utils.LocalResponseCancelled, cmd)
+	}
+
+	if c.httpClient != nil {
+		resp := c.send(ctx, cmd)
+		if !resp.IsSuccess() && ctx.Err() != nil {
+			return makeCancelledResponse(ctx, utils.LocalResponseCancelled, cmd)
+		}
+		return resp
+	}
+
+	// The SDK has no way to pass a context into the HTTP request, so stop waiting for it instead
+	// The abandoned request still runs into the SDK's socket timeout at the latest
+	respChan := make(chan *response.Response, 1)
+	go func() {
+		respChan <- c.send(ctx, cmd)
+	}()
+
+	select {
+	case resp := <-respChan:
+		return resp
+	case <-ctx.Done():
+		return makeCancelledResponse(ctx, utils.LocalResponseAbandoned, cmd)
+	}
+}
+
+func makeSuccessResponse() *response.Response {
+	tpl := responsetemplatemanager.GetInstance().GenerateTemplate("200", "Command completed successfully")
+	return response.NewResponse(tpl, map[string]string{})
+}
+
+func makeCancelledResponse(ctx context.Context, reason utils.LocalResponseReason, cmd map[string]interface{}) *response.Response {
+	return makeLocalResponse("421", "Command cancelled: "+context.Cause(ctx).Error(), reason, cmd)
+}
+
+// Builds an error response marked as generated by the provider, so it can be told apart from API errors
+func makeLocalResponse(code string, description string, reason utils.LocalResponseReason, cmd map[string]interface{}) *response.Response {
+	resp := makeErrorResponse(code, description, cmd)
+	resp.GetCommand()[utils.LocalResponseParam] = string(reason)
+	return resp
+}
+
+// Builds a response for a command which never reached the API
+func makeErrorResponse(code string, description string, cmd map[string]interface{}) *response.Response {
+	tpl := responsetemplatemanager.GetInstance().GenerateTemplate(code, description)
+	return response.NewResponse(tpl, flattenCommand(cmd))
+}
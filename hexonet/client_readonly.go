@@ -0,0 +1,39 @@
+package hexonet
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+)
+
+// Command prefixes which never change anything, all other commands are blocked in read-only mode
+var readOnlyCommandPrefixes = []string{
+	"STATUS",
+	"CHECK",
+	"QUERY",
+	"CONVERTIDN",
+}
+
+// Client wrapper which refuses to send any command that could change objects
+type readOnlyClient struct {
+	Client
+}
+
+func isReadOnlyCommand(command string) bool {
+	command = strings.ToUpper(command)
+	for _, prefix := range readOnlyCommandPrefixes {
+		if strings.HasPrefix(command, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *readOnlyClient) Request(ctx context.Context, cmd map[string]interface{}) *response.Response {
+	if !isReadOnlyCommand(cmd["COMMAND"].(string)) {
+		return makeLocalResponse("549", "Command blocked by read-only mode", utils.LocalResponseReadOnly, cmd)
+	}
+	return c.Client.Request(ctx, cmd)
+}
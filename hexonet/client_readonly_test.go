@@ -0,0 +1,38 @@
+package hexonet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet"
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetDomain(testDomainName, fakeapi.Object{})
+	cl := hexonet.NewReadOnlyClient(newFakeClient(t, fake))
+
+	resp := cl.Request(context.Background(), map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  testDomainName,
+	})
+	if !resp.IsSuccess() {
+		t.Fatalf("expected StatusDomain to be sent, got %d %s", resp.GetCode(), resp.GetDescription())
+	}
+
+	resp = cl.Request(context.Background(), map[string]interface{}{
+		"COMMAND":    "ModifyDomain",
+		"DOMAIN":     testDomainName,
+		"NAMESERVER": []string{"ns1.example.net"},
+	})
+	var readOnlyErr *utils.ReadOnlyError
+	if !errors.As(utils.ClassifyResponse(resp), &readOnlyErr) {
+		t.Fatalf("expected a read-only error, got %d %s", resp.GetCode(), resp.GetDescription())
+	}
+	if n := len(commandsNamed(fake, "ModifyDomain")); n != 0 {
+		t.Errorf("expected ModifyDomain not to be sent, got %d", n)
+	}
+}
@@ -0,0 +1,97 @@
+package hexonet
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+)
+
+const DEFAULT_MAX_RETRIES = 3
+const DEFAULT_MIN_BACKOFF = 1 * time.Second
+const DEFAULT_MAX_BACKOFF = 30 * time.Second
+
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client wrapper which retries temporary failures (including connection errors) with jittered exponential backoff
+type retryingClient struct {
+	Client
+	policy RetryPolicy
+}
+
+func newRetryingClient(cl Client, policy RetryPolicy) *retryingClient {
+	return &retryingClient{
+		Client: cl,
+		policy: policy,
+	}
+}
+
+// Object identifying parameter for Add commands whose success can be verified with the matching Status command
+var idempotencyCheckParams = map[string]string{
+	"ADDDOMAIN":     "DOMAIN",
+	"ADDNAMESERVER": "NAMESERVER",
+}
+
+func isRetryableResponse(resp *response.Response) bool {
+	err := utils.ClassifyResponse(resp)
+	var tempErr *utils.TemporaryError
+	var rateErr *utils.RateLimitedError
+	return errors.As(err, &tempErr) || errors.As(err, &rateErr)
+}
+
+func (c *retryingClient) backoff(attempt int) time.Duration {
+	delay := c.policy.MinBackoff << attempt
+	if delay > c.policy.MaxBackoff || delay <= 0 {
+		delay = c.policy.MaxBackoff
+	}
+	// Jitter between half and the full delay, so parallel operations do not retry in lockstep
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+func (c *retryingClient) Request(ctx context.Context, cmd map[string]interface{}) *response.Response {
+	command := strings.ToUpper(cmd["COMMAND"].(string))
+	isIdempotent := strings.HasPrefix(command, "STATUS") || strings.HasPrefix(command, "MODIFY")
+	checkParam, hasIdempotencyCheck := idempotencyCheckParams[command]
+
+	resp := c.Client.Request(ctx, cmd)
+	for attempt := 0; attempt < c.policy.MaxRetries && isRetryableResponse(resp); attempt++ {
+		if !isIdempotent && !hasIdempotencyCheck {
+			break
+		}
+
+		select {
+		case <-time.After(c.backoff(attempt)):
+		case <-ctx.Done():
+			return resp
+		}
+
+		if hasIdempotencyCheck {
+			// The failed Add might still have gone through, in which case retrying it would fail (or worse)
+			statusResp := c.Client.Request(ctx, map[string]interface{}{
+				"COMMAND":  "Status" + cmd["COMMAND"].(string)[len("Add"):],
+				checkParam: cmd[checkParam],
+			})
+			if statusResp.IsSuccess() {
+				return statusResp
+			}
+
+			var notFoundErr *utils.NotFoundError
+			if !errors.As(utils.ClassifyResponse(statusResp), &notFoundErr) {
+				break
+			}
+		}
+
+		resp = c.Client.Request(ctx, cmd)
+	}
+
+	return resp
+}
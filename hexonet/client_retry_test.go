@@ -0,0 +1,135 @@
+package hexonet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet"
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+)
+
+var testRetryPolicy = hexonet.RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: time.Millisecond,
+}
+
+func TestRetryingClient_retriesIdempotentCommands(t *testing.T) {
+	for _, command := range []string{"StatusDomain", "ModifyDomain"} {
+		t.Run(command, func(t *testing.T) {
+			fake := newFakeServer(t)
+			fake.SetDomain(testDomainName, fakeapi.Object{})
+			fake.InjectFailure(command, 2, fakeapi.CodeTemporaryError, false)
+			cl := hexonet.NewRetryingClient(newFakeClient(t, fake), testRetryPolicy)
+
+			resp := cl.Request(context.Background(), map[string]interface{}{
+				"COMMAND": command,
+				"DOMAIN":  testDomainName,
+			})
+			if !resp.IsSuccess() {
+				t.Fatalf("expected success after retrying, got %d %s", resp.GetCode(), resp.GetDescription())
+			}
+			if n := len(commandsNamed(fake, command)); n != 3 {
+				t.Fatalf("expected 3 attempts, got %d", n)
+			}
+		})
+	}
+}
+
+func TestRetryingClient_givesUpAfterMaxRetries(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetDomain(testDomainName, fakeapi.Object{})
+	fake.InjectFailure("StatusDomain", 10, fakeapi.CodeTemporaryError, false)
+	cl := hexonet.NewRetryingClient(newFakeClient(t, fake), testRetryPolicy)
+
+	resp := cl.Request(context.Background(), map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  testDomainName,
+	})
+	if resp.GetCode() != fakeapi.CodeTemporaryError {
+		t.Fatalf("expected the temporary error, got %d %s", resp.GetCode(), resp.GetDescription())
+	}
+	if n := len(commandsNamed(fake, "StatusDomain")); n != testRetryPolicy.MaxRetries+1 {
+		t.Fatalf("expected %d attempts, got %d", testRetryPolicy.MaxRetries+1, n)
+	}
+}
+
+func TestRetryingClient_neverRetriesOtherCommands(t *testing.T) {
+	for _, cmd := range []map[string]interface{}{
+		{
+			"COMMAND": "DeleteDomain",
+			"DOMAIN":  testDomainName,
+		},
+		{
+			"COMMAND":   "AddContact",
+			"NEW":       "1",
+			"FIRSTNAME": "Jane",
+			"LASTNAME":  "Doe",
+			"STREET0":   "1 Main Street",
+			"CITY":      "Springfield",
+			"ZIP":       "12345",
+			"COUNTRY":   "US",
+			"PHONE":     "+1.5555555555",
+			"EMAIL":     "jane@example.com",
+		},
+	} {
+		command := cmd["COMMAND"].(string)
+		t.Run(command, func(t *testing.T) {
+			fake := newFakeServer(t)
+			fake.SetDomain(testDomainName, fakeapi.Object{})
+			// The command went through, only the response got lost, so retrying it would delete or create twice
+			fake.InjectFailure(command, 1, fakeapi.CodeTemporaryError, true)
+			cl := hexonet.NewRetryingClient(newFakeClient(t, fake), testRetryPolicy)
+
+			resp := cl.Request(context.Background(), cmd)
+			if resp.GetCode() != fakeapi.CodeTemporaryError {
+				t.Fatalf("expected the temporary error, got %d %s", resp.GetCode(), resp.GetDescription())
+			}
+			if n := len(fake.CommandLog()); n != 1 {
+				t.Fatalf("expected only the failed %s to be sent, got %d commands", command, n)
+			}
+		})
+	}
+}
+
+func TestRetryingClient_checksAddBeforeRetrying(t *testing.T) {
+	addDomain := map[string]interface{}{
+		"COMMAND": "AddDomain",
+		"DOMAIN":  testDomainName,
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		fake := newFakeServer(t)
+		fake.InjectFailure("AddDomain", 1, fakeapi.CodeTemporaryError, true)
+		cl := hexonet.NewRetryingClient(newFakeClient(t, fake), testRetryPolicy)
+
+		resp := cl.Request(context.Background(), addDomain)
+		if !resp.IsSuccess() {
+			t.Fatalf("expected the StatusDomain success, got %d %s", resp.GetCode(), resp.GetDescription())
+		}
+		if resp.GetCommand()["COMMAND"] != "StatusDomain" {
+			t.Fatalf("expected the response of StatusDomain, got %s", resp.GetCommand()["COMMAND"])
+		}
+		if n := len(commandsNamed(fake, "AddDomain")); n != 1 {
+			t.Fatalf("expected the domain to be registered once, got %d AddDomain", n)
+		}
+	})
+
+	t.Run("not applied", func(t *testing.T) {
+		fake := newFakeServer(t)
+		fake.InjectFailure("AddDomain", 1, fakeapi.CodeTemporaryError, false)
+		cl := hexonet.NewRetryingClient(newFakeClient(t, fake), testRetryPolicy)
+
+		resp := cl.Request(context.Background(), addDomain)
+		if !resp.IsSuccess() || resp.GetCommand()["COMMAND"] != "AddDomain" {
+			t.Fatalf("expected AddDomain to be retried, got %d %s", resp.GetCode(), resp.GetDescription())
+		}
+		if n := len(commandsNamed(fake, "AddDomain")); n != 2 {
+			t.Fatalf("expected 2 AddDomain, got %d", n)
+		}
+		if fake.Domain(testDomainName) == nil {
+			t.Fatalf("domain was not registered")
+		}
+	})
+}
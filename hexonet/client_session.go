@@ -0,0 +1,156 @@
+package hexonet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+)
+
+// Client wrapper which logs in again and replays the request once the API session has expired
+type sessionClient struct {
+	Client
+	// Performs a full login, including generating a fresh MFA token if needed
+	login func() *response.Response
+	// Optional, keeps the session across runs instead of logging out
+	cache *sessionCache
+
+	// Requests share the lock, re-logins need it exclusively as they change the session of the underlying client
+	lock       sync.RWMutex
+	generation uint64
+	loggedOut  bool
+}
+
+var activeSessionsLock sync.Mutex
+var activeSessions = map[*sessionClient]bool{}
+
+func newSessionClient(cl Client, login func() *response.Response, cache *sessionCache) *sessionClient {
+	return &sessionClient{
+		Client: cl,
+		login:  login,
+		cache:  cache,
+	}
+}
+
+func isSessionExpiredResponse(resp *response.Response) bool {
+	var authErr *utils.AuthenticationError
+	if !errors.As(utils.ClassifyResponse(resp), &authErr) {
+		return false
+	}
+	return strings.Contains(strings.ToUpper(authErr.Description), "SESSION")
+}
+
+func (c *sessionClient) Request(ctx context.Context, cmd map[string]interface{}) *response.Response {
+	c.lock.RLock()
+	generation := c.generation
+	resp := c.Client.Request(ctx, cmd)
+	c.lock.RUnlock()
+
+	if !isSessionExpiredResponse(resp) {
+		return resp
+	}
+
+	loginResp := c.relogin(generation)
+	if !loginResp.IsSuccess() {
+		// The login failure explains more than the expired session does
+		return loginResp
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.Client.Request(ctx, cmd)
+}
+
+func (c *sessionClient) relogin(generation uint64) *response.Response {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.generation != generation {
+		// Another request already logged in again while this one was waiting
+		return makeSuccessResponse()
+	}
+
+	resp, _ := c.doLogin()
+	if resp.IsSuccess() {
+		c.generation++
+	}
+	return resp
+}
+
+// Starts the session, reusing the cached one if there is any
+// Cache errors are returned separately, they never stop the login itself
+func (c *sessionClient) start() (*response.Response, error) {
+	resp, err := c.resumeOrLogin()
+	if resp.IsSuccess() {
+		activeSessionsLock.Lock()
+		activeSessions[c] = true
+		activeSessionsLock.Unlock()
+	}
+	return resp, err
+}
+
+func (c *sessionClient) resumeOrLogin() (*response.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cache != nil {
+		session, err := c.cache.load()
+		if err != nil {
+			resp, _ := c.doLogin()
+			return resp, err
+		}
+		if session != "" {
+			// Whether it is still valid shows on the first request, which logs in again if not
+			c.Client.UseSession(session)
+			return makeSuccessResponse(), nil
+		}
+	}
+
+	return c.doLogin()
+}
+
+func (c *sessionClient) doLogin() (*response.Response, error) {
+	resp := c.login()
+	if !resp.IsSuccess() || c.cache == nil {
+		return resp, nil
+	}
+
+	session, err := c.Client.GetSession()
+	if err != nil {
+		return resp, err
+	}
+	return resp, c.cache.store(session)
+}
+
+func (c *sessionClient) Logout() *response.Response {
+	activeSessionsLock.Lock()
+	delete(activeSessions, c)
+	activeSessionsLock.Unlock()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.loggedOut || c.cache != nil {
+		// Cached sessions are kept for the next run, they expire on their own
+		return makeSuccessResponse()
+	}
+	c.loggedOut = true
+	return c.Client.Logout()
+}
+
+// LogoutAll ends the API sessions of all configured providers, meant to be called when the plugin server stops
+func LogoutAll() {
+	activeSessionsLock.Lock()
+	sessions := make([]*sessionClient, 0, len(activeSessions))
+	for c := range activeSessions {
+		sessions = append(sessions, c)
+	}
+	activeSessionsLock.Unlock()
+
+	for _, c := range sessions {
+		c.Logout()
+	}
+}
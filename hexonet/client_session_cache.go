@@ -0,0 +1,112 @@
+package hexonet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// File storing API sessions across Terraform runs, so not every run needs a new login (and MFA token)
+// The file holds a JSON object mapping cache keys to session IDs, so multiple accounts can share it
+type sessionCache struct {
+	path string
+	key  string
+}
+
+func newSessionCache(path string, cfg *ClientConfig) *sessionCache {
+	system := "ote"
+	if cfg.Live {
+		system = "live"
+	}
+	key := fmt.Sprintf("%s!%s@%s", cfg.Username, cfg.Role, system)
+	// Sessions of the live and OT&E system are separate even behind the same endpoint
+	if cfg.EndpointURL != "" {
+		key += "@" + cfg.EndpointURL
+	}
+
+	return &sessionCache{
+		path: path,
+		key:  key,
+	}
+}
+
+func (c *sessionCache) readAll() (map[string]string, error) {
+	sessions := make(map[string]string)
+
+	info, err := os.Stat(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Windows does not have Unix permissions, it reports every writable file as 0666
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		// Anyone else able to read the file could take over the sessions, so do not trust it
+		return nil, fmt.Errorf("session cache file %s must only be accessible by its owner (mode 0600)", c.path)
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return sessions, nil
+	}
+	err = json.Unmarshal(data, &sessions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session cache file %s: %w", c.path, err)
+	}
+	return sessions, nil
+}
+
+// Returns the cached session, or an empty string if there is none
+func (c *sessionCache) load() (string, error) {
+	sessions, err := c.readAll()
+	if err != nil {
+		return "", err
+	}
+	return sessions[c.key], nil
+}
+
+// Stores (or with an empty session removes) the cached session
+func (c *sessionCache) store(session string) error {
+	sessions, err := c.readAll()
+	if err != nil {
+		// A broken cache file is replaced instead of blocking every future run
+		sessions = make(map[string]string)
+	}
+
+	if session == "" {
+		delete(sessions, c.key)
+	} else {
+		sessions[c.key] = session
+	}
+
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so parallel runs never see a partially written file
+	tmpFile, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	// CreateTemp already restricts the file to its owner
+	_, err = tmpFile.Write(data)
+	closeErr := tmpFile.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(tmpFile.Name(), c.path)
+}
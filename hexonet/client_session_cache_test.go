@@ -0,0 +1,40 @@
+package hexonet
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionCacheKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	const endpoint = "https://api.example.com/api/call.cgi"
+
+	live := &ClientConfig{Username: "user", Role: "role", Live: true, EndpointURL: endpoint}
+	if err := newSessionCache(path, live).store("live-session"); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *ClientConfig
+		expected string
+	}{
+		{"same account", &ClientConfig{Username: "user", Role: "role", Live: true, EndpointURL: endpoint}, "live-session"},
+		// Both systems can be reached through the same endpoint, their sessions must not mix
+		{"OT&E behind the same endpoint", &ClientConfig{Username: "user", Role: "role", Live: false, EndpointURL: endpoint}, ""},
+		{"default endpoint", &ClientConfig{Username: "user", Role: "role", Live: true}, ""},
+		{"other role", &ClientConfig{Username: "user", Role: "other", Live: true, EndpointURL: endpoint}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := newSessionCache(path, tt.cfg).load()
+			if err != nil {
+				t.Fatalf("load failed: %v", err)
+			}
+			if session != tt.expected {
+				t.Errorf("expected session %q, got %q", tt.expected, session)
+			}
+		})
+	}
+}
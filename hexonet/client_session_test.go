@@ -0,0 +1,94 @@
+package hexonet_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet"
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+)
+
+// Starts a session client against the fake, counting the logins it performs
+func startCountingSessionClient(t *testing.T, fake *fakeapi.Server) (hexonet.Client, *atomic.Int32) {
+	cl := fake.ClientFactory()(&hexonet.ClientConfig{
+		Username: testUsername,
+		Password: testPassword,
+	})
+	logins := &atomic.Int32{}
+	sc, resp := hexonet.StartSessionClient(cl, func() *response.Response {
+		logins.Add(1)
+		return cl.Login()
+	})
+	if !resp.IsSuccess() {
+		t.Fatalf("login failed: %s", resp.GetDescription())
+	}
+	return sc, logins
+}
+
+func TestSessionClient_reloginAfterExpiry(t *testing.T) {
+	fake := newFakeServer(t)
+	sc, logins := startCountingSessionClient(t, fake)
+	if fake.SessionCount() != 1 {
+		t.Fatalf("expected 1 session, got %d", fake.SessionCount())
+	}
+
+	fake.ExpireSessions()
+
+	resp := sc.Request(context.Background(), map[string]interface{}{"COMMAND": "StatusAccount"})
+	if !resp.IsSuccess() {
+		t.Fatalf("expected the replayed request to succeed, got %d %s", resp.GetCode(), resp.GetDescription())
+	}
+	if n := logins.Load(); n != 2 {
+		t.Errorf("expected one login again, got %d logins in total", n)
+	}
+	// The request rejected for the expired session never reached the handler
+	if n := len(commandsNamed(fake, "StatusAccount")); n != 1 {
+		t.Errorf("expected the request to be replayed once, got %d", n)
+	}
+	if fake.SessionCount() != 1 {
+		t.Errorf("expected 1 session, got %d", fake.SessionCount())
+	}
+}
+
+func TestSessionClient_concurrentReloginOnce(t *testing.T) {
+	fake := newFakeServer(t)
+	sc, logins := startCountingSessionClient(t, fake)
+
+	fake.ExpireSessions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := sc.Request(context.Background(), map[string]interface{}{"COMMAND": "StatusAccount"})
+			if !resp.IsSuccess() {
+				t.Errorf("expected the request to succeed, got %d %s", resp.GetCode(), resp.GetDescription())
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Requests which ran into the expired session together share one login
+	if n := logins.Load(); n != 2 {
+		t.Errorf("expected one login again, got %d logins in total", n)
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	fake := newFakeServer(t)
+	startCountingSessionClient(t, fake)
+	startCountingSessionClient(t, fake)
+	if fake.SessionCount() != 2 {
+		t.Fatalf("expected 2 sessions, got %d", fake.SessionCount())
+	}
+
+	hexonet.LogoutAll()
+
+	if fake.SessionCount() != 0 {
+		t.Errorf("expected all sessions to end, %d are left", fake.SessionCount())
+	}
+}
@@ -0,0 +1,162 @@
+package hexonet
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/responsetemplatemanager"
+)
+
+// The SDK builds a new http.Client for every request, which only allows setting a proxy
+// Clients with a custom transport (for a CA bundle) or User-Agent suffix send their requests themselves instead, the same way the SDK does
+// Unlike the SDK's, these requests are aborted when the context of the command ends
+
+// Same as the socket timeout of the SDK
+const sdkSocketTimeout = 300 * time.Second
+
+// Parameters the SDK converts to punycode, using the API as it knows which TLDs allow which characters
+var idnParamPattern = regexp.MustCompile(`(?i)^(DOMAIN|NAMESERVER|DNSZONE)([0-9]*)$`)
+var idnValuePattern = regexp.MustCompile(`(?i)[^a-z0-9. -]+`)
+
+func (c *sdkClient) send(ctx context.Context, cmd map[string]interface{}) *response.Response {
+	if c.httpClient == nil {
+		return c.APIClient.Request(cmd)
+	}
+	return c.post(ctx, c.convertIDN(ctx, flattenCommand(cmd)))
+}
+
+func (c *sdkClient) post(ctx context.Context, cmd map[string]string) *response.Response {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.GetURL(), strings.NewReader(c.GetPOSTData(cmd)))
+	if err != nil {
+		return makeHTTPErrorResponse(err, cmd)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Expect", "")
+	req.Header.Add("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return makeHTTPErrorResponse(err, cmd)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return makeHTTPErrorResponse(fmt.Errorf("unexpected HTTP status %s", resp.Status), cmd)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return makeHTTPErrorResponse(err, cmd)
+	}
+	return response.NewResponse(string(body), cmd, map[string]string{"CONNECTION_URL": c.GetURL()})
+}
+
+func (c *sdkClient) convertIDN(ctx context.Context, cmd map[string]string) map[string]string {
+	if strings.EqualFold(cmd["COMMAND"], "ConvertIDN") {
+		return cmd
+	}
+
+	convertCmd := map[string]string{"COMMAND": "ConvertIDN"}
+	keys := make([]string, 0)
+	for key, val := range cmd {
+		if idnParamPattern.MatchString(key) && idnValuePattern.MatchString(val) {
+			convertCmd[fmt.Sprintf("DOMAIN%d", len(keys))] = val
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return cmd
+	}
+
+	// Like the SDK, the values are sent as they are if the conversion fails, the API then reports them as invalid
+	resp := c.post(ctx, convertCmd)
+	if !resp.IsSuccess() {
+		return cmd
+	}
+	col := resp.GetColumn("ACE")
+	if col == nil {
+		return cmd
+	}
+	for idx, ace := range col.GetData() {
+		if idx < len(keys) {
+			cmd[keys[idx]] = ace
+		}
+	}
+	return cmd
+}
+
+// Translates a command to the plain form sent to the API, lists become numbered parameters
+func flattenCommand(cmd map[string]interface{}) map[string]string {
+	res := make(map[string]string, len(cmd))
+	for key, val := range cmd {
+		key = strings.ToUpper(key)
+		switch v := val.(type) {
+		case []string:
+			for idx, str := range v {
+				res[key+strconv.Itoa(idx)] = stripLineBreaks(str)
+			}
+		case string:
+			res[key] = stripLineBreaks(v)
+		}
+	}
+	return res
+}
+
+func stripLineBreaks(val string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(val)
+}
+
+func makeHTTPErrorResponse(err error, cmd map[string]string) *response.Response {
+	tpl := responsetemplatemanager.GetInstance().GenerateTemplate("421", "Command failed due to HTTP communication error: "+stripLineBreaks(err.Error()))
+	return response.NewResponse(tpl, cmd)
+}
+
+// Builds a transport trusting the system CAs plus the ones in caBundleFile (if set), using proxyURL if set
+func makeTransport(caBundleFile string, proxyURL string) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if base, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = base.Clone()
+	}
+
+	if caBundleFile != "" {
+		pem, err := os.ReadFile(caBundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", caBundleFile)
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
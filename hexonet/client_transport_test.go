@@ -0,0 +1,183 @@
+package hexonet_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet"
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+type countingTransport struct {
+	requests atomic.Int32
+	err      error
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSDKClient_customTransport(t *testing.T) {
+	fake := newFakeServer(t)
+	defaultTransport := http.DefaultTransport
+	transport := &countingTransport{}
+
+	cl := fake.ClientFactory()(&hexonet.ClientConfig{
+		Username:  testUsername,
+		Password:  testPassword,
+		Transport: transport,
+	})
+
+	if resp := cl.Login(); !resp.IsSuccess() {
+		t.Fatalf("login failed: %s", resp.GetDescription())
+	}
+	if fake.SessionCount() != 1 {
+		t.Fatalf("expected 1 session, got %d", fake.SessionCount())
+	}
+	resp := cl.Request(context.Background(), map[string]interface{}{
+		"COMMAND":    "StatusDomain",
+		"DOMAIN":     "example.com",
+		"NAMESERVER": []string{"ns1.example.net"},
+	})
+	if resp.GetCode() != 545 {
+		t.Errorf("expected 545 for the missing domain, got %d %s", resp.GetCode(), resp.GetDescription())
+	}
+	if resp := cl.Logout(); !resp.IsSuccess() {
+		t.Fatalf("logout failed: %s", resp.GetDescription())
+	}
+	if fake.SessionCount() != 0 {
+		t.Errorf("expected the session to end, %d are left", fake.SessionCount())
+	}
+
+	if n := transport.requests.Load(); n != 3 {
+		t.Errorf("expected all 3 requests to use the transport, got %d", n)
+	}
+	cmd, err := lastCommandNamed(fake, "StatusDomain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd["NAMESERVER0"] != "ns1.example.net" {
+		t.Errorf("expected lists to be sent as numbered parameters, got %v", cmd)
+	}
+	if http.DefaultTransport != defaultTransport {
+		t.Errorf("the default transport must not be replaced")
+	}
+}
+
+func TestSDKClient_customTransportError(t *testing.T) {
+	fake := newFakeServer(t)
+	transport := &countingTransport{err: errors.New("certificate signed by unknown authority")}
+
+	cl := fake.ClientFactory()(&hexonet.ClientConfig{
+		Username:  testUsername,
+		Password:  testPassword,
+		Transport: transport,
+	})
+
+	resp := cl.Login()
+	if resp.GetCode() != 421 || !strings.Contains(resp.GetDescription(), "certificate signed by unknown authority") {
+		t.Errorf("expected the transport error in a 421 response, got %d %s", resp.GetCode(), resp.GetDescription())
+	}
+}
+
+// Holds all requests until their context ends
+type blockingTransport struct {
+	aborted atomic.Bool
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	t.aborted.Store(true)
+	return nil, req.Context().Err()
+}
+
+func TestSDKClient_customTransportCancel(t *testing.T) {
+	transport := &blockingTransport{}
+	cl := hexonet.NewSDKClient(apiclient.NewAPIClient(), &hexonet.ClientConfig{
+		Transport: transport,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp := cl.Request(ctx, map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  "example.com",
+	})
+
+	if !transport.aborted.Load() {
+		t.Errorf("expected the HTTP request to be aborted with the context")
+	}
+	if reason := utils.LocalResponseReasonOf(resp); resp.GetCode() != 421 || reason != utils.LocalResponseCancelled {
+		t.Errorf("expected a cancelled response, got %d %s (%q)", resp.GetCode(), resp.GetDescription(), reason)
+	}
+}
+
+func TestSDKClient_abandonedRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	sdk := apiclient.NewAPIClient()
+	sdk.SetURL(server.URL)
+	cl := hexonet.NewSDKClient(sdk, &hexonet.ClientConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp := cl.Request(ctx, map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  "example.com",
+	})
+
+	// The SDK request cannot be aborted, so the diagnostic has to say that it may still go through
+	if reason := utils.LocalResponseReasonOf(resp); resp.GetCode() != 421 || reason != utils.LocalResponseAbandoned {
+		t.Fatalf("expected an abandoned response, got %d %s (%q)", resp.GetCode(), resp.GetDescription(), reason)
+	}
+	diags := diag.Diagnostics{}
+	utils.HandlePossibleErrorResponse(ctx, resp, &diags)
+	if len(diags) != 1 || diags[0].Summary() != "Operation timed out" || !strings.Contains(diags[0].Detail(), "may still complete") {
+		t.Errorf("expected a timeout noting that the command may still complete, got %v", diags)
+	}
+	if strings.Contains(diags[0].Detail(), utils.LocalResponseParam) {
+		t.Errorf("the local response marker must not show up in diagnostics: %s", diags[0].Detail())
+	}
+}
+
+func TestSDKClient_userAgentSuffix(t *testing.T) {
+	fake := newFakeServer(t)
+	defaultUserAgent := apiclient.NewAPIClient().GetUserAgent()
+
+	cl := fake.ClientFactory()(&hexonet.ClientConfig{
+		Username:        testUsername,
+		Password:        testPassword,
+		UserAgentSuffix: "deploy-pipeline/1.2",
+	})
+	if resp := cl.Login(); !resp.IsSuccess() {
+		t.Fatalf("login failed: %s", resp.GetDescription())
+	}
+	if ua := fake.LastUserAgent(); ua != defaultUserAgent+" deploy-pipeline/1.2" {
+		t.Errorf("expected the suffix after the default User-Agent %q, got %q", defaultUserAgent, ua)
+	}
+
+	cl.Request(context.Background(), map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  "example.com",
+	})
+	if ua := fake.LastUserAgent(); ua != defaultUserAgent+" deploy-pipeline/1.2" {
+		t.Errorf("expected the suffix after the default User-Agent %q, got %q", defaultUserAgent, ua)
+	}
+}
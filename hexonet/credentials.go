@@ -0,0 +1,146 @@
+package hexonet
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const DEFAULT_CREDENTIALS_PROFILE = "default"
+
+// Credentials read from a credentials file profile or a credential process
+type credentialSet struct {
+	Username      string `json:"username"`
+	Role          string `json:"role"`
+	Password      string `json:"password"`
+	MfaTotpSecret string `json:"mfa_totp_secret"`
+}
+
+// Values already set in c win over the ones in other
+func (c *credentialSet) merge(other *credentialSet) {
+	if c.Username == "" {
+		c.Username = other.Username
+	}
+	if c.Role == "" {
+		c.Role = other.Role
+	}
+	if c.Password == "" {
+		c.Password = other.Password
+	}
+	if c.MfaTotpSecret == "" {
+		c.MfaTotpSecret = other.MfaTotpSecret
+	}
+}
+
+// Reads a profile from a credentials file, which can either be INI:
+//
+//	[default]
+//	username = ...
+//	password = ...
+//
+// or JSON mapping profile names to objects with the same keys
+func loadCredentialsFile(path string, profile string) (*credentialSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file: %w", err)
+	}
+
+	var profiles map[string]*credentialSet
+	if strings.HasPrefix(string(bytes.TrimSpace(data)), "{") {
+		err = json.Unmarshal(data, &profiles)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON in credentials file %s: %w", path, err)
+		}
+	} else {
+		profiles, err = parseCredentialsINI(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INI in credentials file %s: %w", path, err)
+		}
+	}
+
+	creds := profiles[profile]
+	if creds == nil {
+		return nil, fmt.Errorf("profile %s not found in credentials file %s", profile, path)
+	}
+	return creds, nil
+}
+
+func parseCredentialsINI(data []byte) (map[string]*credentialSet, error) {
+	profiles := make(map[string]*credentialSet)
+	var current *credentialSet
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			current = profiles[name]
+			if current == nil {
+				current = &credentialSet{}
+				profiles[name] = current
+			}
+			continue
+		}
+
+		key, val, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: key outside of a [profile] section", lineNo)
+		}
+
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "username":
+			current.Username = val
+		case "role":
+			current.Role = val
+		case "password":
+			current.Password = val
+		case "mfa_totp_secret":
+			current.MfaTotpSecret = val
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %s", lineNo, strings.TrimSpace(key))
+		}
+	}
+
+	return profiles, scanner.Err()
+}
+
+// Runs the credential process through the shell and parses the JSON object it prints
+func runCredentialProcess(ctx context.Context, command string) (*credentialSet, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// Only stderr is included, stdout might contain (partial) secrets
+		return nil, fmt.Errorf("credential process failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	creds := &credentialSet{}
+	err = json.Unmarshal(out, creds)
+	if err != nil {
+		return nil, fmt.Errorf("credential process did not print a valid JSON object: %w", err)
+	}
+	return creds, nil
+}
@@ -47,7 +47,7 @@ func (d *dataSourceContact) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	data = kindContactRead(data, d.p.client, &resp.Diagnostics)
+	data = kindContactRead(ctx, data, d.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -2,6 +2,7 @@ package hexonet
 
 import (
 	"context"
+	"time"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -51,6 +52,7 @@ func (d *dataSourceDomain) Read(ctx context.Context, req datasource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	checkDomainExpiry(data, d.p.expiryThresholds, time.Now(), true, false, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -0,0 +1,181 @@
+package hexonet
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type dataSourceDomainCheckData struct {
+	Domains types.List `tfsdk:"domains"`
+	Results types.Map  `tfsdk:"results"`
+}
+
+type domainCheckResultData struct {
+	Available         types.Bool    `tfsdk:"available"`
+	Premium           types.Bool    `tfsdk:"premium"`
+	PremiumClass      types.String  `tfsdk:"premium_class"`
+	RegistrationPrice types.Float64 `tfsdk:"registration_price"`
+	RenewalPrice      types.Float64 `tfsdk:"renewal_price"`
+	TransferPrice     types.Float64 `tfsdk:"transfer_price"`
+	Currency          types.String  `tfsdk:"currency"`
+	Reason            types.String  `tfsdk:"reason"`
+}
+
+var domainCheckResultAttrTypes = map[string]attr.Type{
+	"available":          types.BoolType,
+	"premium":            types.BoolType,
+	"premium_class":      types.StringType,
+	"registration_price": types.Float64Type,
+	"renewal_price":      types.Float64Type,
+	"transfer_price":     types.Float64Type,
+	"currency":           types.StringType,
+	"reason":             types.StringType,
+}
+
+type dataSourceDomainCheck struct {
+	p *localProvider
+}
+
+func newDataSourceDomainCheck() datasource.DataSource {
+	return &dataSourceDomainCheck{}
+}
+
+func (r *dataSourceDomainCheck) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"domains": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+				},
+				Description: "Domain names to check (example: [\"example.com\"])",
+			},
+			"results": schema.MapNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"available": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the domain can be registered",
+						},
+						"premium": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the domain is a premium domain (registering it needs accept_premium_price on hexonet_domain)",
+						},
+						"premium_class": schema.StringAttribute{
+							Computed:    true,
+							Description: "Premium class of the domain",
+						},
+						"registration_price": schema.Float64Attribute{
+							Computed:    true,
+							Description: "Price to register the domain for one year, the premium price or the price of the account for standard domains, null if the API has none",
+						},
+						"renewal_price": schema.Float64Attribute{
+							Computed:    true,
+							Description: "Price to renew the domain for one year, null if the API has none",
+						},
+						"transfer_price": schema.Float64Attribute{
+							Computed:    true,
+							Description: "Price to transfer the domain, null if the API has none",
+						},
+						"currency": schema.StringAttribute{
+							Computed:    true,
+							Description: "Currency of the prices",
+						},
+						"reason": schema.StringAttribute{
+							Computed:    true,
+							Description: "Why the domain is (not) available, as returned by the API",
+						},
+					},
+				},
+				Description: "Check results keyed by the domain names as given in domains",
+			},
+		},
+		Description: "Availability and pricing of domains (CheckDomains, plus the account prices from StatusUser for standard domains), for example to use in preconditions before registering them",
+	}
+}
+
+func (d *dataSourceDomainCheck) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.p = req.ProviderData.(*localProvider)
+}
+
+func (d *dataSourceDomainCheck) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_check"
+}
+
+func priceToFloat64(price string) types.Float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(price), 64)
+	if err != nil {
+		return types.Float64Null()
+	}
+	return types.Float64Value(f)
+}
+
+func (d *dataSourceDomainCheck) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if !d.p.configured {
+		utils.MakeNotConfiguredError(&resp.Diagnostics)
+		return
+	}
+
+	data := &dataSourceDomainCheckData{}
+	diags := req.Config.Get(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var domains []string
+	resp.Diagnostics.Append(data.Domains.ElementsAs(ctx, &domains, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checks := makeDomainCheckCommand(ctx, d.p.client, domains, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fillAccountDomainPrices(ctx, d.p.client, checks, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	results := make(map[string]domainCheckResultData, len(checks))
+	for _, check := range checks {
+		results[check.Domain] = domainCheckResultData{
+			Available:         types.BoolValue(check.Available),
+			Premium:           types.BoolValue(check.Premium),
+			PremiumClass:      utils.AutoBoxString(check.Class),
+			RegistrationPrice: priceToFloat64(check.Price),
+			RenewalPrice:      priceToFloat64(check.RenewalPrice),
+			TransferPrice:     priceToFloat64(check.TransferPrice),
+			Currency:          utils.AutoBoxString(check.Currency),
+			Reason:            utils.AutoBoxString(check.Reason),
+		}
+	}
+
+	data.Results, diags = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: domainCheckResultAttrTypes}, results)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,42 @@
+package hexonet_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDomainCheck_prices(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetPremiumDomain("premium.com", "PREMIUM_TEST", 500, "USD")
+	fake.SetAccountPrice("com", 12.5, 10, "USD")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig(false, "") + `
+data "hexonet_domain_check" "test" {
+  domains = ["premium.com", "example.com", "example.net"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Premium prices come from CheckDomains
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.premium.com.premium", "true"),
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.premium.com.registration_price", "500"),
+					// Standard domains use the prices of the account
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.com.premium", "false"),
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.com.registration_price", "12.5"),
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.com.renewal_price", "12.5"),
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.com.transfer_price", "10"),
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.com.currency", "USD"),
+					// Without a price in the account, the prices stay null
+					resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.net.available", "true"),
+					resource.TestCheckNoResourceAttr("data.hexonet_domain_check.test", "results.example.net.registration_price"),
+					resource.TestCheckNoResourceAttr("data.hexonet_domain_check.test", "results.example.net.currency"),
+					testCheckCommandCount(fake, "StatusUser", 1),
+				),
+			},
+		},
+	})
+}
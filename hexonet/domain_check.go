@@ -0,0 +1,126 @@
+package hexonet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Result of CheckDomains for a single domain
+type domainCheckResult struct {
+	Domain      string
+	Code        int
+	Description string
+	Available   bool
+	Premium     bool
+	Class       string
+	// Prices for one year as returned by the API, usually only set for premium domains (see fillAccountDomainPrices)
+	Price         string
+	RenewalPrice  string
+	TransferPrice string
+	Currency      string
+	// Why the domain is (not) available
+	Reason string
+}
+
+// Checks availability and premium pricing of the domains, results are in the same order
+func makeDomainCheckCommand(ctx context.Context, cl Client, domains []string, diags *diag.Diagnostics) []*domainCheckResult {
+	req := map[string]interface{}{
+		"COMMAND":         "CheckDomains",
+		"PREMIUMCHANNELS": "*",
+	}
+	for i, domain := range domains {
+		req[fmt.Sprintf("DOMAIN%d", i)] = domain
+	}
+
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	res := make([]*domainCheckResult, 0, len(domains))
+	for i, domain := range domains {
+		check := utils.ColumnIndexOrDefault(resp, "DOMAINCHECK", "", i).(string)
+		codeStr, description, _ := strings.Cut(check, " ")
+		code, err := strconv.Atoi(codeStr)
+		if err != nil {
+			diags.AddError("Invalid CheckDomains response", fmt.Sprintf("Unexpected DOMAINCHECK value %q for %s", check, domain))
+			return nil
+		}
+
+		desc := strings.ToLower(description)
+		class := utils.ColumnIndexOrDefault(resp, "CLASS", "", i).(string)
+		reason := utils.ColumnIndexOrDefault(resp, "REASON", "", i).(string)
+		if reason == "" {
+			reason = description
+		}
+		res = append(res, &domainCheckResult{
+			Domain:      domain,
+			Code:        code,
+			Description: description,
+			// Premium domains are reported as 211 as well, but with "available" in the description
+			Available:     code == 210 || (code == 211 && strings.Contains(desc, "available") && !strings.Contains(desc, "not available")),
+			Premium:       class != "" || strings.Contains(desc, "premium"),
+			Class:         class,
+			Price:         utils.ColumnIndexOrDefault(resp, "PRICE", "", i).(string),
+			RenewalPrice:  utils.ColumnIndexOrDefault(resp, "RENEWALPRICE", "", i).(string),
+			TransferPrice: utils.ColumnIndexOrDefault(resp, "TRANSFERPRICE", "", i).(string),
+			Currency:      utils.ColumnIndexOrDefault(resp, "CURRENCY", "", i).(string),
+			Reason:        reason,
+		})
+	}
+	return res
+}
+
+// Name of the price class of a domain in the price relations of the account (example: CO_UK for example.co.uk)
+func domainPriceClass(domain string) string {
+	_, tld, _ := strings.Cut(domain, ".")
+	return strings.ToUpper(strings.ReplaceAll(tld, ".", "_"))
+}
+
+// CheckDomains only returns prices for premium domains, the account prices of standard domains come from the
+// price relations of the user (StatusUser), for example PRICE_CLASS_DOMAIN_COM_ANNUAL
+// Prices the account has no relation for stay empty
+func fillAccountDomainPrices(ctx context.Context, cl Client, checks []*domainCheckResult, diags *diag.Diagnostics) {
+	missing := make([]*domainCheckResult, 0)
+	for _, check := range checks {
+		if !check.Premium && check.Price == "" {
+			missing = append(missing, check)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	resp := cl.Request(ctx, map[string]interface{}{
+		"COMMAND": "StatusUser",
+	})
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	if diags.HasError() {
+		return
+	}
+
+	relationTypes := utils.ColumnOrDefault(resp, "RELATIONTYPE", []string{})
+	relationValues := utils.ColumnOrDefault(resp, "RELATIONVALUE", []string{})
+	relations := make(map[string]string, len(relationTypes))
+	for i, relationType := range relationTypes {
+		if i < len(relationValues) {
+			relations[strings.ToUpper(relationType)] = relationValues[i]
+		}
+	}
+
+	for _, check := range missing {
+		prefix := "PRICE_CLASS_DOMAIN_" + domainPriceClass(check.Domain)
+		check.Price = relations[prefix+"_ANNUAL"]
+		check.RenewalPrice = relations[prefix+"_ANNUAL"]
+		check.TransferPrice = relations[prefix+"_TRANSFER"]
+		if check.Currency == "" {
+			check.Currency = relations[prefix+"_CURRENCY"]
+		}
+	}
+}
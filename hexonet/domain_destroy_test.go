@@ -0,0 +1,124 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testOnDestroyDomainConfig(allowDomainCreateDelete bool, onDestroy string) string {
+	return providerConfig(allowDomainCreateDelete, "") + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain      = %q
+  create_mode = "adopt"
+  on_destroy  = %q
+}
+`, testDomainName, onDestroy)
+}
+
+func newFakeServerWithDomain(t *testing.T) *fakeapi.Server {
+	fake := newFakeServer(t)
+	fake.SetDomain(testDomainName, fakeapi.Object{
+		"NAMESERVER":  {"ns1.example.net", "ns2.example.net"},
+		"RENEWALMODE": {"AUTORENEW"},
+	})
+	return fake
+}
+
+func testCheckFakeDomainExists(fake *fakeapi.Server) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		if fake.Domain(testDomainName) == nil {
+			return fmt.Errorf("domain %s does not exist anymore", testDomainName)
+		}
+		return nil
+	}
+}
+
+func TestAccDomain_onDestroy(t *testing.T) {
+	destroyCommands := []string{"DeleteDomain", "SetDomainRenewalMode", "PushDomain"}
+
+	tests := []struct {
+		onDestroy string
+		// The only command destroying may send, if any
+		command     string
+		checkFake   func(fake *fakeapi.Server) func(*terraform.State) error
+		commandArgs map[string]string
+	}{
+		{"forget", "", testCheckFakeDomainExists, nil},
+		{"delete", "DeleteDomain", testCheckDomainDestroyed, map[string]string{"DOMAIN": testDomainName}},
+		{"autoexpire", "SetDomainRenewalMode", testCheckFakeDomainExists, map[string]string{"DOMAIN": testDomainName, "RENEWALMODE": "AUTOEXPIRE"}},
+		{"push", "PushDomain", testCheckDomainDestroyed, map[string]string{"DOMAIN": testDomainName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.onDestroy, func(t *testing.T) {
+			fake := newFakeServerWithDomain(t)
+
+			checks := []resource.TestCheckFunc{tt.checkFake(fake)}
+			for _, command := range destroyCommands {
+				expected := 0
+				if command == tt.command {
+					expected = 1
+				}
+				checks = append(checks, testCheckCommandCount(fake, command, expected))
+			}
+			if tt.command != "" {
+				checks = append(checks, testCheckLastCommand(fake, tt.command, tt.commandArgs))
+			}
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+				CheckDestroy:             resource.ComposeAggregateTestCheckFunc(checks...),
+				Steps: []resource.TestStep{
+					{
+						Config: testOnDestroyDomainConfig(true, tt.onDestroy),
+						Check:  resource.TestCheckResourceAttr("hexonet_domain.test", "on_destroy", tt.onDestroy),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccDomain_onDestroyNotAllowed(t *testing.T) {
+	fake := newFakeServerWithDomain(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testCheckFakeDomainExists(fake),
+			testCheckCommandCount(fake, "DeleteDomain", 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				// Plans which do not destroy the domain only warn about the mode
+				Config: testOnDestroyDomainConfig(false, "delete"),
+				Check:  resource.TestCheckResourceAttr("hexonet_domain.test", "on_destroy", "delete"),
+			},
+			{
+				Config:      testOnDestroyDomainConfig(false, "delete"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`on_destroy delete needs allow_domain_create_delete to be enabled on the\s+provider`),
+			},
+			{
+				// Replacing the domain destroys the old one as well
+				Config: providerConfig(false, "") + `
+resource "hexonet_domain" "test" {
+  domain      = "example.net"
+  create_mode = "adopt"
+  on_destroy  = "delete"
+}
+`,
+				ExpectError: regexp.MustCompile(`on_destroy delete needs allow_domain_create_delete to be enabled on the\s+provider`),
+			},
+			{
+				Config: testOnDestroyDomainConfig(false, "forget"),
+				Check:  testCheckCommandCount(fake, "DeleteDomain", 0),
+			},
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package hexonet
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Days before the registration expires from which plans warn or fail, 0 disables the check
+type expiryThresholds struct {
+	warningDays int64
+	errorDays   int64
+	// Renewal mode the account applies to domains with DEFAULT, "" if unknown, then those are not checked
+	accountRenewalMode string
+}
+
+// Per-domain settings win over the provider ones, null keeps the provider setting
+func (t expiryThresholds) override(warningDays types.Int64, errorDays types.Int64) expiryThresholds {
+	if !warningDays.IsNull() && !warningDays.IsUnknown() {
+		t.warningDays = warningDays.ValueInt64()
+	}
+	if !errorDays.IsNull() && !errorDays.IsUnknown() {
+		t.errorDays = errorDays.ValueInt64()
+	}
+	return t
+}
+
+// Domains with these renewal modes do not lapse at the end of their term
+func isRenewingMode(renewalMode string) bool {
+	return renewalMode == RENEWAL_MODE_AUTORENEW || renewalMode == RENEWAL_MODE_RENEWONCE
+}
+
+// Adds a warning or error if the domain expires within the thresholds and is not going to be renewed
+// Without fail, domains within expiry_error_days get a warning instead, without warn those within expiry_warning_days get nothing
+func checkDomainExpiry(domain *Domain, t expiryThresholds, now time.Time, warn bool, fail bool, diags *diag.Diagnostics) {
+	if t.warningDays <= 0 && t.errorDays <= 0 {
+		return
+	}
+	if domain.RegistrationExpirationDate.IsNull() || domain.RegistrationExpirationDate.IsUnknown() || domain.RenewalMode.IsUnknown() {
+		return
+	}
+
+	renewalMode := domain.RenewalMode.ValueString()
+	renewalModeDescription := renewalMode
+	if renewalMode == RENEWAL_MODE_DEFAULT {
+		if t.accountRenewalMode == "" {
+			return
+		}
+		renewalMode = t.accountRenewalMode
+		renewalModeDescription = fmt.Sprintf("%s (%s in the account)", RENEWAL_MODE_DEFAULT, renewalMode)
+	}
+	if isRenewingMode(renewalMode) {
+		return
+	}
+
+	expiration, err := time.Parse(time.RFC3339, domain.RegistrationExpirationDate.ValueString())
+	if err != nil {
+		// Dates the API returned in an unknown format can not be checked
+		return
+	}
+
+	days := int64(math.Floor(expiration.Sub(now).Hours() / 24))
+	detail := fmt.Sprintf("Domain %s expires in %d days (%s) and its renewal mode is %s, set renewal_mode to %s or renew it to keep it", domain.Domain.ValueString(), days, domain.RegistrationExpirationDate.ValueString(), renewalModeDescription, RENEWAL_MODE_AUTORENEW)
+	if days < 0 {
+		detail = fmt.Sprintf("Domain %s expired %d days ago (%s) and its renewal mode is %s", domain.Domain.ValueString(), -days, domain.RegistrationExpirationDate.ValueString(), renewalModeDescription)
+	}
+
+	attrPath := path.Root("registration_expiration_date")
+	switch {
+	case t.errorDays > 0 && days < t.errorDays && fail:
+		diags.AddAttributeError(attrPath, "Domain expiring", detail+fmt.Sprintf(" (expiry_error_days is %d)", t.errorDays))
+	case t.errorDays > 0 && days < t.errorDays && warn:
+		diags.AddAttributeWarning(attrPath, "Domain expiring", detail+fmt.Sprintf(" (expiry_error_days is %d)", t.errorDays))
+	case t.warningDays > 0 && days < t.warningDays && warn:
+		diags.AddAttributeWarning(attrPath, "Domain expiring soon", detail+fmt.Sprintf(" (expiry_warning_days is %d)", t.warningDays))
+	}
+}
@@ -0,0 +1,59 @@
+package hexonet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckDomainExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Expires in 10 days, so within both thresholds
+	expiration := types.StringValue("2026-01-11T00:00:00Z")
+	thresholds := expiryThresholds{warningDays: 60, errorDays: 30}
+
+	tests := []struct {
+		name               string
+		renewalMode        string
+		accountRenewalMode string
+		warn               bool
+		fail               bool
+		severity           diag.Severity
+	}{
+		{"renewing", RENEWAL_MODE_AUTORENEW, "", true, true, diag.SeverityInvalid},
+		{"expiring", RENEWAL_MODE_AUTOEXPIRE, "", true, true, diag.SeverityError},
+		// Read only warns, even within expiry_error_days
+		{"expiring when reading", RENEWAL_MODE_AUTOEXPIRE, "", true, false, diag.SeverityWarning},
+		{"expiring without warnings", RENEWAL_MODE_AUTOEXPIRE, "", false, false, diag.SeverityInvalid},
+		// What DEFAULT means depends on the account, which is only known if configured
+		{"default of unknown account", RENEWAL_MODE_DEFAULT, "", true, true, diag.SeverityInvalid},
+		{"default of renewing account", RENEWAL_MODE_DEFAULT, RENEWAL_MODE_AUTORENEW, true, true, diag.SeverityInvalid},
+		{"default of expiring account", RENEWAL_MODE_DEFAULT, RENEWAL_MODE_AUTOEXPIRE, true, true, diag.SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain := &Domain{
+				Domain:                     types.StringValue("example.com"),
+				RenewalMode:                types.StringValue(tt.renewalMode),
+				RegistrationExpirationDate: expiration,
+			}
+			th := thresholds
+			th.accountRenewalMode = tt.accountRenewalMode
+
+			diags := diag.Diagnostics{}
+			checkDomainExpiry(domain, th, now, tt.warn, tt.fail, &diags)
+			if tt.severity == diag.SeverityInvalid {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Severity() != tt.severity {
+				t.Fatalf("expected one diagnostic of severity %v, got %v", tt.severity, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testExpiringDomainConfig(renewalMode string) string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain            = %q
+  renewal_mode      = %q
+  expiry_error_days = 30
+}
+`, testDomainName, renewalMode)
+}
+
+func TestAccDomain_expiryCheckUsesState(t *testing.T) {
+	fake := newFakeServer(t)
+	expiration := time.Now().UTC().AddDate(0, 0, 10).Format("2006-01-02 15:04:05")
+	fake.SetDomain(testDomainName, fakeapi.Object{
+		"OWNERCONTACT":               {"P-EXISTING"},
+		"REGISTRATIONEXPIRATIONDATE": {expiration},
+		"PAIDUNTILDATE":              {expiration},
+		"RENEWALDATE":                {expiration},
+		"RENEWALMODE":                {"AUTOEXPIRE"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				// Renewing keeps the domain, so its expiry is fine
+				Config: testExpiringDomainConfig("AUTORENEW"),
+				Check:  resource.TestCheckResourceAttr("hexonet_domain.test", "renewal_mode", "AUTORENEW"),
+			},
+			{
+				// The dates are unknown in the plan, the check uses the ones in the state
+				Config:      testExpiringDomainConfig("AUTOEXPIRE"),
+				ExpectError: regexp.MustCompile("Domain expiring"),
+			},
+		},
+	})
+}
+
+func TestAccDomain_expiryCheckDefaultRenewalMode(t *testing.T) {
+	fake := newFakeServer(t)
+	expiration := time.Now().UTC().AddDate(0, 0, 10).Format("2006-01-02 15:04:05")
+	fake.SetDomain(testDomainName, fakeapi.Object{
+		"OWNERCONTACT":               {"P-EXISTING"},
+		"REGISTRATIONEXPIRATIONDATE": {expiration},
+		"PAIDUNTILDATE":              {expiration},
+		"RENEWALDATE":                {expiration},
+		"RENEWALMODE":                {"DEFAULT"},
+	})
+	config := func(providerExtra string) string {
+		return providerConfig(false, providerExtra) + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain            = %q
+  expiry_error_days = 30
+}
+`, testDomainName)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				// Without knowing what DEFAULT means for the account, the domain is not checked
+				Config: config(""),
+				Check:  resource.TestCheckResourceAttr("hexonet_domain.test", "renewal_mode", "DEFAULT"),
+			},
+			{
+				Config:      config(`account_renewal_mode = "AUTOEXPIRE"`),
+				ExpectError: regexp.MustCompile(`mode\s+is\s+DEFAULT\s+\(AUTOEXPIRE\s+in\s+the\s+account\)`),
+			},
+			{
+				Config: config(`account_renewal_mode = "AUTORENEW"`),
+				Check:  resource.TestCheckResourceAttr("hexonet_domain.test", "renewal_mode", "DEFAULT"),
+			},
+		},
+	})
+}
@@ -0,0 +1,151 @@
+package hexonet
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type domainOperation = string
+
+const (
+	domainOperationCreate domainOperation = "create"
+	domainOperationDelete domainOperation = "delete"
+)
+
+type domainProtectionData struct {
+	AllowCreate types.List `tfsdk:"allow_create"`
+	DenyCreate  types.List `tfsdk:"deny_create"`
+	AllowDelete types.List `tfsdk:"allow_delete"`
+	DenyDelete  types.List `tfsdk:"deny_delete"`
+}
+
+func makeDomainProtectionSchema() schema.Block {
+	patternDescription := "patterns are globs (example: *.com) unless they start with ^, then they are regular expressions (example: ^test-.*\\.dev$), patterns have to match the whole domain name, matching is case-insensitive"
+	return schema.SingleNestedBlock{
+		Attributes: map[string]schema.Attribute{
+			"allow_create": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "If set, only domains matching one of these patterns can be registered or transferred in (an empty list allows none), " + patternDescription,
+			},
+			"deny_create": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Domains matching one of these patterns are never registered or transferred in (takes precedence over allow_create), " + patternDescription,
+			},
+			"allow_delete": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "If set, only domains matching one of these patterns can be deleted (an empty list allows none), " + patternDescription,
+			},
+			"deny_delete": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Domains matching one of these patterns are never deleted (takes precedence over allow_delete), " + patternDescription,
+			},
+		},
+		Description: "Rules restricting which domains can be registered or deleted, on top of allow_domain_create_delete",
+	}
+}
+
+type domainPattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+type domainRuleSet struct {
+	// nil if the allow list is not set, which allows all domains, an empty list allows none
+	allow []domainPattern
+	deny  []domainPattern
+}
+
+// Compiled domain_protection rules, the zero value allows everything
+type domainProtection struct {
+	rules map[domainOperation]*domainRuleSet
+}
+
+func compileDomainPattern(raw string) (domainPattern, error) {
+	// Regular expressions are anchored at the end as well, so ^example\.com does not match example.com.evil.net
+	expr := "^(?:" + strings.TrimPrefix(raw, "^") + ")$"
+	if !strings.HasPrefix(raw, "^") {
+		expr = "^" + regexp.QuoteMeta(raw) + "$"
+		expr = strings.ReplaceAll(expr, "\\*", ".*")
+		expr = strings.ReplaceAll(expr, "\\?", ".")
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return domainPattern{}, fmt.Errorf("invalid domain_protection pattern %q: %w", raw, err)
+	}
+	return domainPattern{raw: raw, re: re}, nil
+}
+
+func compileDomainPatterns(ctx context.Context, list types.List, diags *diag.Diagnostics) []domainPattern {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+
+	var raws []string
+	diags.Append(list.ElementsAs(ctx, &raws, false)...)
+
+	res := make([]domainPattern, 0, len(raws))
+	for _, raw := range raws {
+		pattern, err := compileDomainPattern(raw)
+		if err != nil {
+			diags.AddError("Can not configure client", err.Error())
+			continue
+		}
+		res = append(res, pattern)
+	}
+	return res
+}
+
+func makeDomainProtection(ctx context.Context, data *domainProtectionData, diags *diag.Diagnostics) *domainProtection {
+	res := &domainProtection{
+		rules: make(map[domainOperation]*domainRuleSet),
+	}
+	if data == nil {
+		return res
+	}
+
+	res.rules[domainOperationCreate] = &domainRuleSet{
+		allow: compileDomainPatterns(ctx, data.AllowCreate, diags),
+		deny:  compileDomainPatterns(ctx, data.DenyCreate, diags),
+	}
+	res.rules[domainOperationDelete] = &domainRuleSet{
+		allow: compileDomainPatterns(ctx, data.AllowDelete, diags),
+		deny:  compileDomainPatterns(ctx, data.DenyDelete, diags),
+	}
+	return res
+}
+
+// Adds an error if the rules do not allow the operation for the domain
+func (p *domainProtection) check(op domainOperation, domain string, diags *diag.Diagnostics) {
+	if p == nil || p.rules[op] == nil {
+		return
+	}
+	rules := p.rules[op]
+
+	for _, pattern := range rules.deny {
+		if pattern.re.MatchString(domain) {
+			diags.AddError("Blocked by domain protection", fmt.Sprintf("Domain %s matches deny_%s pattern %q, so it can not be %sd", domain, op, pattern.raw, op))
+			return
+		}
+	}
+
+	if rules.allow == nil {
+		return
+	}
+	for _, pattern := range rules.allow {
+		if pattern.re.MatchString(domain) {
+			return
+		}
+	}
+	diags.AddError("Blocked by domain protection", fmt.Sprintf("Domain %s does not match any allow_%s pattern, so it can not be %sd", domain, op, op))
+}
@@ -0,0 +1,86 @@
+package hexonet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// nil patterns give a null list, an empty slice an empty one
+func makeTestPatternList(patterns []string) types.List {
+	if patterns == nil {
+		return types.ListNull(types.StringType)
+	}
+	elems := make([]attr.Value, 0, len(patterns))
+	for _, pattern := range patterns {
+		elems = append(elems, types.StringValue(pattern))
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+func TestDomainProtectionCheck(t *testing.T) {
+	protection := makeDomainProtection(context.Background(), &domainProtectionData{
+		AllowCreate: makeTestPatternList([]string{"*.com", `^test-[0-9]+\.dev`}),
+		DenyCreate:  makeTestPatternList([]string{"*bank*.com"}),
+		AllowDelete: makeTestPatternList([]string{}),
+		DenyDelete:  makeTestPatternList(nil),
+	}, &diag.Diagnostics{})
+
+	tests := []struct {
+		name    string
+		op      domainOperation
+		domain  string
+		allowed bool
+	}{
+		{"allowed glob", domainOperationCreate, "example.com", true},
+		{"case-insensitive", domainOperationCreate, "EXAMPLE.COM", true},
+		{"not allowed", domainOperationCreate, "example.net", false},
+		// deny wins even if an allow pattern matches as well
+		{"denied before allowed", domainOperationCreate, "mybank.com", false},
+		{"allowed regex", domainOperationCreate, "test-42.dev", true},
+		// Regular expressions have to match the whole name, not just its start
+		{"regex anchored at the end", domainOperationCreate, "test-42.dev.example.net", false},
+		{"glob anchored at the start", domainOperationCreate, "example.com.evil.net", false},
+		// An empty allow list allows nothing, unlike an unset one
+		{"empty allow list", domainOperationDelete, "example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := diag.Diagnostics{}
+			protection.check(tt.op, tt.domain, &diags)
+			if diags.HasError() == tt.allowed {
+				t.Errorf("expected allowed %t, got %v", tt.allowed, diags)
+			}
+		})
+	}
+}
+
+func TestDomainProtectionUnset(t *testing.T) {
+	for _, data := range []*domainProtectionData{
+		nil,
+		{
+			AllowCreate: makeTestPatternList([]string{"*.com"}),
+			DenyCreate:  makeTestPatternList(nil),
+			AllowDelete: makeTestPatternList(nil),
+			DenyDelete:  makeTestPatternList(nil),
+		},
+	} {
+		protection := makeDomainProtection(context.Background(), data, &diag.Diagnostics{})
+		diags := diag.Diagnostics{}
+		// Without allow_delete and deny_delete all deletions are allowed
+		protection.check(domainOperationDelete, "example.com", &diags)
+		if diags.HasError() {
+			t.Errorf("expected deletion to be allowed, got %v", diags)
+		}
+	}
+}
+
+func TestCompileDomainPatternInvalid(t *testing.T) {
+	if _, err := compileDomainPattern("^(unclosed"); err == nil {
+		t.Errorf("expected an error for an invalid regular expression")
+	}
+}
@@ -0,0 +1,110 @@
+package hexonet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const TRANSFER_POLL_MIN_INTERVAL = 1 * time.Second
+const TRANSFER_POLL_MAX_INTERVAL = 1 * time.Minute
+
+// Transfer states after which the transfer will never complete
+var failedTransferStatus = []string{"FAILED", "REJECTED", "CANCELLED", "DENIED", "NACK"}
+
+func makeDomainTransferCommand(ctx context.Context, cl Client, domain *Domain, authCode types.String, diags *diag.Diagnostics) *response.Response {
+	if domain.Domain.IsNull() || domain.Domain.IsUnknown() {
+		diags.AddError("Main ID attribute unknwon or null", "domain is null or unknown")
+		return nil
+	}
+	if authCode.IsNull() || authCode.IsUnknown() || authCode.ValueString() == "" {
+		diags.AddError("Missing auth code", "transfer_auth_code is required to transfer a domain")
+		return nil
+	}
+
+	req := map[string]interface{}{
+		"COMMAND": "TransferDomain",
+		"DOMAIN":  domain.Domain.ValueString(),
+		"ACTION":  "REQUEST",
+		"AUTH":    authCode.ValueString(),
+	}
+
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	return resp
+}
+
+// Returns the status of the pending transfer of the domain, or an empty string if there is none
+func queryDomainTransfer(ctx context.Context, cl Client, domain *Domain, diags *diag.Diagnostics) (string, *response.Response) {
+	resp := cl.Request(ctx, map[string]interface{}{
+		"COMMAND": "StatusDomainTransfer",
+		"DOMAIN":  domain.Domain.ValueString(),
+	})
+
+	respDiags := diag.Diagnostics{}
+	utils.HandlePossibleErrorResponse(ctx, resp, &respDiags)
+	if utils.IsNotFound(respDiags) {
+		return "", resp
+	}
+	diags.Append(respDiags...)
+	if diags.HasError() {
+		return "", resp
+	}
+
+	status := utils.ColumnFirstOrDefault(resp, "TRANSFERSTATUS", "").(string)
+	if status == "" {
+		// The API only lists transfers which are still running
+		status = "PENDING"
+	}
+	return strings.ToUpper(status), resp
+}
+
+func isFailedTransferStatus(status string) bool {
+	for _, failed := range failedTransferStatus {
+		if strings.Contains(status, failed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Polls until the transfer of the domain is no longer pending, the context bounds how long this waits
+// Transfers usually take days, so running into the timeout does not cancel the transfer itself
+func waitForDomainTransfer(ctx context.Context, cl Client, domain *Domain, diags *diag.Diagnostics) {
+	interval := TRANSFER_POLL_MIN_INTERVAL
+	for {
+		status, resp := queryDomainTransfer(ctx, cl, domain, diags)
+		if diags.HasError() || status == "" {
+			return
+		}
+
+		if isFailedTransferStatus(status) {
+			diags.AddError(
+				"Domain transfer failed",
+				fmt.Sprintf("Transfer of %s ended with status %s: %s", domain.Domain.ValueString(), status, utils.ColumnFirstOrDefault(resp, "TRANSFERLOG", "no reason given")),
+			)
+			return
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			diags.AddError(
+				"Domain transfer still pending",
+				fmt.Sprintf("Transfer of %s did not finish within the create timeout (last status %s), it keeps running at the registry, apply again to continue waiting for it or raise the create timeout", domain.Domain.ValueString(), status),
+			)
+			return
+		}
+
+		interval *= 2
+		if interval > TRANSFER_POLL_MAX_INTERVAL {
+			interval = TRANSFER_POLL_MAX_INTERVAL
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testTransferAuthCode = "transfer-secret"
+
+func testTransferDomainConfig(extra string) string {
+	return providerConfig(true, "") + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain             = %q
+  create_mode        = "transfer"
+  transfer_auth_code = %q
+  name_servers       = ["ns1.example.net", "ns2.example.net"]
+  %s
+}
+`, testDomainName, testTransferAuthCode, extra)
+}
+
+func TestAccDomain_transfer(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetForeignDomain(testDomainName, testTransferAuthCode)
+	fake.TransferPolls = 1
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testTransferDomainConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "create_mode", "transfer"),
+					testCheckCommandCount(fake, "TransferDomain", 1),
+					testCheckLastCommand(fake, "TransferDomain", map[string]string{
+						"ACTION": "REQUEST",
+						"AUTH":   testTransferAuthCode,
+					}),
+					// Checked once for an earlier transfer, then polled until the transfer is no longer pending
+					testCheckCommandCount(fake, "StatusDomainTransfer", 3),
+					// The transferred domain is brought in line with the config like an adopted one
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns1.example.net", "ns2.example.net"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomain_transferRejected(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetForeignDomain(testDomainName, testTransferAuthCode)
+	fake.RejectTransfer(testDomainName, "rejected by the losing registrar")
+	fake.TransferPolls = 1
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config:      testTransferDomainConfig(""),
+				ExpectError: regexp.MustCompile(`ended with status FAILED: rejected by the losing\s+registrar`),
+			},
+		},
+	})
+}
+
+func TestAccDomain_transferStillPending(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetForeignDomain(testDomainName, testTransferAuthCode)
+	// Polled after 0s and 1s, the next poll after another 2s would come too late for the timeout
+	fake.TransferPolls = 3
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testTransferDomainConfig(`
+  timeouts {
+    create = "2s"
+  }
+`),
+				ExpectError: regexp.MustCompile("Domain transfer still pending"),
+			},
+			{
+				// Applying again waits for the running transfer instead of requesting another one
+				Config: testTransferDomainConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "create_mode", "transfer"),
+					testCheckCommandCount(fake, "TransferDomain", 1),
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns1.example.net", "ns2.example.net"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomain_transferRetried(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetForeignDomain(testDomainName, testTransferAuthCode)
+	fake.RejectTransfer(testDomainName, "rejected by the losing registrar")
+	fake.TransferPolls = 1
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config:      testTransferDomainConfig(""),
+				ExpectError: regexp.MustCompile(`ended with status FAILED`),
+			},
+			{
+				// The failed transfer is still reported, applying again has to request a new one
+				PreConfig: func() {
+					fake.RejectTransfer(testDomainName, "")
+				},
+				Config: testTransferDomainConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "create_mode", "transfer"),
+					testCheckCommandCount(fake, "TransferDomain", 2),
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns1.example.net", "ns2.example.net"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,20 @@
+package hexonet
+
+import "github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+
+// The fake API imports this package, so the tests using it live in hexonet_test and reach the internals through these
+
+func NewRetryingClient(cl Client, policy RetryPolicy) Client {
+	return newRetryingClient(cl, policy)
+}
+
+// Starts a session client without a session cache, as the provider does for every configured account
+func StartSessionClient(cl Client, login func() *response.Response) (Client, *response.Response) {
+	c := newSessionClient(cl, login, nil)
+	resp, _ := c.start()
+	return c, resp
+}
+
+func NewReadOnlyClient(cl Client) Client {
+	return &readOnlyClient{Client: cl}
+}
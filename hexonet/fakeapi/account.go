@@ -0,0 +1,43 @@
+package fakeapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func handleStatusAccount(s *Server, _ Command, _ string) *Response {
+	return SuccessResponse(Object{
+		"USER":     {s.Username},
+		"AMOUNT":   {"0.00"},
+		"CURRENCY": {"USD"},
+	})
+}
+
+// SetAccountPrice sets the prices of standard domains with the given TLD (example: co.uk), as the account's price relations
+func (s *Server) SetAccountPrice(tld string, annual float64, transfer float64, currency string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	prefix := "PRICE_CLASS_DOMAIN_" + strings.ToUpper(strings.ReplaceAll(tld, ".", "_"))
+	s.priceRelations[prefix+"_ANNUAL"] = strconv.FormatFloat(annual, 'f', 2, 64)
+	s.priceRelations[prefix+"_TRANSFER"] = strconv.FormatFloat(transfer, 'f', 2, 64)
+	s.priceRelations[prefix+"_CURRENCY"] = currency
+}
+
+// Lists the price relations of the user as RELATIONTYPE/RELATIONVALUE pairs
+func handleStatusUser(s *Server, _ Command, _ string) *Response {
+	types := make([]string, 0, len(s.priceRelations))
+	for relationType := range s.priceRelations {
+		types = append(types, relationType)
+	}
+	sort.Strings(types)
+
+	props := Object{
+		"USER": {s.Username},
+	}
+	for _, relationType := range types {
+		props["RELATIONTYPE"] = append(props["RELATIONTYPE"], relationType)
+		props["RELATIONVALUE"] = append(props["RELATIONVALUE"], s.priceRelations[relationType])
+	}
+	return SuccessResponse(props)
+}
@@ -0,0 +1,107 @@
+package fakeapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indexedParams returns the values of all "<prefix><n>" parameters ordered by n
+func indexedParams(cmd Command, prefix string) ([]string, bool) {
+	type indexedValue struct {
+		idx int
+		val string
+	}
+
+	found := false
+	values := make([]indexedValue, 0)
+	for k, v := range cmd {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		idx, err := strconv.Atoi(k[len(prefix):])
+		if err != nil || idx < 0 {
+			continue
+		}
+		found = true
+		values = append(values, indexedValue{idx: idx, val: v})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].idx < values[j].idx
+	})
+
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, v.val)
+	}
+	return res, found
+}
+
+// applyArray updates a list property the way the API does
+// <PREFIX><n> replaces the whole list (empty values are dropped), DEL<PREFIX><n> removes values and ADD<PREFIX><n> appends values
+func applyArray(obj Object, prefix string, cmd Command) {
+	list := obj[prefix]
+
+	replacement, found := indexedParams(cmd, prefix)
+	if found {
+		list = make([]string, 0, len(replacement))
+		for _, val := range replacement {
+			if val != "" {
+				list = append(list, val)
+			}
+		}
+	}
+
+	deletions, _ := indexedParams(cmd, "DEL"+prefix)
+	for _, del := range deletions {
+		filtered := make([]string, 0, len(list))
+		for _, val := range list {
+			if !strings.EqualFold(val, del) {
+				filtered = append(filtered, val)
+			}
+		}
+		list = filtered
+	}
+
+	additions, _ := indexedParams(cmd, "ADD"+prefix)
+	for _, add := range additions {
+		if add != "" && !containsFold(list, add) {
+			list = append(list, add)
+		}
+	}
+
+	if len(list) == 0 {
+		delete(obj, prefix)
+		return
+	}
+	obj[prefix] = list
+}
+
+// Parameters which only confirm something for the command itself and are not stored
+var transientExtraAttributes = map[string]bool{
+	"X-FEE-AMOUNT": true,
+}
+
+// applyExtraAttributes sets all X- parameters on the object, empty values remove the attribute
+func applyExtraAttributes(obj Object, cmd Command) {
+	for k, v := range cmd {
+		if !strings.HasPrefix(k, "X-") || transientExtraAttributes[k] {
+			continue
+		}
+		if v == "" {
+			delete(obj, k)
+			continue
+		}
+		obj[k] = []string{v}
+	}
+}
+
+func containsFold(list []string, val string) bool {
+	for _, elem := range list {
+		if strings.EqualFold(elem, val) {
+			return true
+		}
+	}
+	return false
+}
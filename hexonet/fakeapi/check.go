@@ -0,0 +1,87 @@
+package fakeapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+type premiumDomain struct {
+	class    string
+	price    float64
+	currency string
+}
+
+// SetPremiumDomain marks an available domain as premium, registering it needs X-FEE-AMOUNT of at least price times PERIOD
+// The same price is reported for registration, renewal and transfer
+func (s *Server) SetPremiumDomain(name string, class string, price float64, currency string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.premiumDomains[strings.ToLower(name)] = &premiumDomain{
+		class:    class,
+		price:    price,
+		currency: currency,
+	}
+}
+
+// Checks DOMAIN or DOMAIN0..n, the columns are indexed like the domains
+func handleCheckDomains(s *Server, cmd Command, _ string) *Response {
+	names, found := indexedParams(cmd, "DOMAIN")
+	if !found {
+		if cmd["DOMAIN"] == "" {
+			return ErrorResponse(CodeMissingAttribute, "Missing required attribute; DOMAIN")
+		}
+		names = []string{cmd["DOMAIN"]}
+	}
+
+	props := Object{}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		check, reason, class, price, currency := "210 Available", "", "", "", ""
+
+		_, registeredElsewhere := s.foreignDomains[name]
+		premium := s.premiumDomains[name]
+		switch {
+		case s.domains[name] != nil || registeredElsewhere || s.pendingTransfers[name] != nil:
+			check = "211 Domain name not available"
+			reason = "Domain exists"
+		case !strings.Contains(name, "."):
+			check = "541 Invalid domain name"
+			reason = "Missing TLD"
+		case premium != nil:
+			check = "211 Premium Domain name available"
+			class = premium.class
+			price = strconv.FormatFloat(premium.price, 'f', 2, 64)
+			currency = premium.currency
+		}
+
+		props["DOMAIN"] = append(props["DOMAIN"], name)
+		props["DOMAINCHECK"] = append(props["DOMAINCHECK"], check)
+		props["CLASS"] = append(props["CLASS"], class)
+		props["REASON"] = append(props["REASON"], reason)
+		props["PRICE"] = append(props["PRICE"], price)
+		props["RENEWALPRICE"] = append(props["RENEWALPRICE"], price)
+		props["TRANSFERPRICE"] = append(props["TRANSFERPRICE"], price)
+		props["CURRENCY"] = append(props["CURRENCY"], currency)
+	}
+
+	return SuccessResponse(props)
+}
+
+// Premium domains can only be registered when the fee is acknowledged
+func (s *Server) checkPremiumFee(name string, cmd Command) *Response {
+	premium := s.premiumDomains[name]
+	if premium == nil {
+		return nil
+	}
+
+	// The fee covers all years registered
+	years, err := strconv.Atoi(cmd["PERIOD"])
+	if err != nil || years < 1 {
+		years = 1
+	}
+	fee, err := strconv.ParseFloat(cmd["X-FEE-AMOUNT"], 64)
+	if err != nil || fee < premium.price*float64(years) || !strings.EqualFold(cmd["CLASS"], premium.class) {
+		return ErrorResponse(CodeCommandFailed, "Command failed; premium domain, fee not acknowledged")
+	}
+	return nil
+}
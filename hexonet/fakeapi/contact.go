@@ -0,0 +1,137 @@
+package fakeapi
+
+import (
+	"strings"
+)
+
+var contactFields = []string{
+	"TITLE",
+	"FIRSTNAME",
+	"MIDDLENAME",
+	"LASTNAME",
+	"ORGANIZATION",
+	"CITY",
+	"STATE",
+	"ZIP",
+	"COUNTRY",
+	"PHONE",
+	"FAX",
+	"EMAIL",
+	"DISCLOSE",
+	"VATID",
+	"IDAUTHORITY",
+	"IDNUMBER",
+}
+
+var contactRequiredFields = []string{
+	"FIRSTNAME",
+	"LASTNAME",
+	"STREET0",
+	"CITY",
+	"ZIP",
+	"COUNTRY",
+	"PHONE",
+	"EMAIL",
+}
+
+func applyContactParams(obj Object, cmd Command) {
+	for _, field := range contactFields {
+		if val := cmd[field]; val != "" {
+			obj[field] = []string{val}
+		}
+	}
+
+	if street, found := indexedParams(cmd, "STREET"); found {
+		// Keep the positions of the address lines intact, only trailing empty lines are dropped
+		for len(street) > 0 && street[len(street)-1] == "" {
+			street = street[:len(street)-1]
+		}
+		if len(street) == 0 {
+			delete(obj, "STREET")
+		} else {
+			obj["STREET"] = street
+		}
+	}
+
+	deletions, _ := indexedParams(cmd, "DELETE")
+	for _, field := range deletions {
+		delete(obj, strings.ToUpper(field))
+	}
+
+	applyExtraAttributes(obj, cmd)
+}
+
+func (s *Server) lookupContact(cmd Command) (string, Object, *Response) {
+	id := strings.ToUpper(cmd["CONTACT"])
+	if id == "" {
+		return "", nil, ErrorResponse(CodeMissingAttribute, "Missing required attribute; CONTACT")
+	}
+	obj := s.contacts[id]
+	if obj == nil {
+		return id, nil, ErrorResponse(CodeObjectNotFound, "Object does not exist")
+	}
+	return id, obj, nil
+}
+
+func (s *Server) contactInUse(id string) bool {
+	for _, domain := range s.domains {
+		for _, column := range []string{"OWNERCONTACT", "ADMINCONTACT", "TECHCONTACT", "BILLINGCONTACT"} {
+			if containsFold(domain[column], id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func handleAddContact(s *Server, cmd Command, _ string) *Response {
+	for _, field := range contactRequiredFields {
+		if cmd[field] == "" {
+			return ErrorResponse(CodeMissingAttribute, "Missing required attribute; "+field)
+		}
+	}
+
+	id := randomID("P-")
+	for s.contacts[id] != nil {
+		id = randomID("P-")
+	}
+
+	obj := Object{
+		"ID": {id},
+	}
+	applyContactParams(obj, cmd)
+	s.contacts[id] = obj
+
+	return SuccessResponse(Object{
+		"CONTACT": {id},
+	})
+}
+
+func handleStatusContact(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupContact(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	return SuccessResponse(copyObject(obj))
+}
+
+func handleModifyContact(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupContact(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	applyContactParams(obj, cmd)
+	return SuccessResponse(nil)
+}
+
+func handleDeleteContact(s *Server, cmd Command, _ string) *Response {
+	id, _, errResp := s.lookupContact(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	if s.contactInUse(id) {
+		return ErrorResponse(CodeCommandFailed, "Command failed; Object is in use")
+	}
+	delete(s.contacts, id)
+	return SuccessResponse(nil)
+}
@@ -0,0 +1,193 @@
+package fakeapi
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout of the timestamps the API returns
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+var domainArrays = []string{
+	"NAMESERVER",
+	"STATUS",
+	"OWNERCONTACT",
+	"ADMINCONTACT",
+	"TECHCONTACT",
+	"BILLINGCONTACT",
+	"SECDNS-DS",
+	"SECDNS-KEY",
+}
+
+func applyDomainParams(obj Object, cmd Command) {
+	for _, prefix := range domainArrays {
+		applyArray(obj, prefix, cmd)
+	}
+
+	if msl, ok := cmd["SECDNS-MAXSIGLIFE"]; ok {
+		if msl == "" || msl == "0" {
+			delete(obj, "SECDNS-MAXSIGLIFE")
+		} else {
+			obj["SECDNS-MAXSIGLIFE"] = []string{msl}
+		}
+	}
+
+	applyExtraAttributes(obj, cmd)
+}
+
+// Builds a freshly registered domain object, with the registry metadata StatusDomain returns
+func newDomainObject(name string, years int) Object {
+	now := time.Now().UTC()
+	expiration := now.AddDate(years, 0, 0).Format(dateTimeLayout)
+
+	repository := "FAKE"
+	if _, tld, found := strings.Cut(name, "."); found {
+		repository = strings.ToUpper(tld) + "-FAKE"
+	}
+
+	return Object{
+		"ID":                         {name},
+		"AUTH":                       {randomID("")},
+		"CREATEDDATE":                {now.Format(dateTimeLayout)},
+		"UPDATEDDATE":                {now.Format(dateTimeLayout)},
+		"REGISTRATIONEXPIRATIONDATE": {expiration},
+		"PAIDUNTILDATE":              {expiration},
+		"RENEWALDATE":                {expiration},
+		"REGISTRAR":                  {"HEXONET"},
+		"REPOSITORY":                 {repository},
+		"TRANSFERLOCK":               {"0"},
+	}
+}
+
+func touchDomain(obj Object) {
+	obj["UPDATEDDATE"] = []string{time.Now().UTC().Format(dateTimeLayout)}
+}
+
+func (s *Server) lookupDomain(cmd Command) (string, Object, *Response) {
+	name := strings.ToLower(cmd["DOMAIN"])
+	if name == "" {
+		return "", nil, ErrorResponse(CodeMissingAttribute, "Missing required attribute; DOMAIN")
+	}
+	obj := s.domains[name]
+	if obj == nil {
+		return name, nil, ErrorResponse(CodeObjectNotFound, "Object does not exist")
+	}
+	return name, obj, nil
+}
+
+func handleAddDomain(s *Server, cmd Command, _ string) *Response {
+	name, obj, errResp := s.lookupDomain(cmd)
+	if obj != nil {
+		return ErrorResponse(CodeObjectExists, "Object exists")
+	}
+	if name == "" {
+		return errResp
+	}
+
+	if errResp := s.checkPremiumFee(name, cmd); errResp != nil {
+		return errResp
+	}
+
+	years := 1
+	if cmd["PERIOD"] != "" {
+		var err error
+		years, err = strconv.Atoi(strings.TrimSuffix(strings.ToUpper(cmd["PERIOD"]), "Y"))
+		if err != nil || years < 1 || years > 10 {
+			return ErrorResponse(CodeInvalidValue, "Invalid attribute value; PERIOD")
+		}
+	}
+
+	obj = newDomainObject(name, years)
+	applyDomainParams(obj, cmd)
+	s.domains[name] = obj
+	delete(s.premiumDomains, name)
+
+	return SuccessResponse(nil)
+}
+
+func handleStatusDomain(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupDomain(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	return SuccessResponse(copyObject(obj))
+}
+
+func handleModifyDomain(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupDomain(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	applyDomainParams(obj, cmd)
+	touchDomain(obj)
+	return SuccessResponse(nil)
+}
+
+func handleDeleteDomain(s *Server, cmd Command, _ string) *Response {
+	name, _, errResp := s.lookupDomain(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	delete(s.domains, name)
+	return SuccessResponse(nil)
+}
+
+func handleSetDomainRenewalMode(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupDomain(cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	mode := strings.ToUpper(cmd["RENEWALMODE"])
+	switch mode {
+	case "DEFAULT", "AUTORENEW", "AUTOEXPIRE", "AUTODELETE", "RENEWONCE":
+		obj["RENEWALMODE"] = []string{mode}
+		touchDomain(obj)
+	case "":
+		return ErrorResponse(CodeMissingAttribute, "Missing required attribute; RENEWALMODE")
+	default:
+		return ErrorResponse(CodeInvalidValue, "Invalid attribute value; RENEWALMODE")
+	}
+	return SuccessResponse(nil)
+}
+
+func handlePushDomain(s *Server, cmd Command, _ string) *Response {
+	name, _, errResp := s.lookupDomain(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	delete(s.domains, name)
+	return SuccessResponse(nil)
+}
+
+func handleRenewDomain(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupDomain(cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	period, err := strconv.Atoi(cmd["PERIOD"])
+	if err != nil || period < 1 || period > 10 {
+		return ErrorResponse(CodeInvalidValue, "Invalid attribute value; PERIOD")
+	}
+
+	var expiration time.Time
+	if raw := obj["REGISTRATIONEXPIRATIONDATE"]; len(raw) > 0 {
+		expiration, err = time.Parse(dateTimeLayout, raw[0])
+	}
+	if err != nil || expiration.IsZero() {
+		return ErrorResponse(CodeCommandFailed, "Command failed; domain has no expiration date")
+	}
+	// The API refuses renewals for a different expiration year, which prevents renewing twice by accident
+	if cmd["EXPIRATION"] != "" && cmd["EXPIRATION"] != strconv.Itoa(expiration.Year()) {
+		return ErrorResponse(CodeCommandFailed, "Command failed; EXPIRATION does not match the current expiration year")
+	}
+
+	renewed := expiration.AddDate(period, 0, 0).Format(dateTimeLayout)
+	obj["REGISTRATIONEXPIRATIONDATE"] = []string{renewed}
+	obj["PAIDUNTILDATE"] = []string{renewed}
+	obj["RENEWALDATE"] = []string{renewed}
+	touchDomain(obj)
+	return SuccessResponse(nil)
+}
@@ -0,0 +1,71 @@
+package fakeapi
+
+import (
+	"strings"
+)
+
+func (s *Server) lookupNameserver(cmd Command) (string, Object, *Response) {
+	host := strings.ToLower(cmd["NAMESERVER"])
+	if host == "" {
+		return "", nil, ErrorResponse(CodeMissingAttribute, "Missing required attribute; NAMESERVER")
+	}
+	obj := s.nameservers[host]
+	if obj == nil {
+		return host, nil, ErrorResponse(CodeObjectNotFound, "Object does not exist")
+	}
+	return host, obj, nil
+}
+
+func handleAddNameserver(s *Server, cmd Command, _ string) *Response {
+	host, obj, errResp := s.lookupNameserver(cmd)
+	if obj != nil {
+		return ErrorResponse(CodeObjectExists, "Object exists")
+	}
+	if host == "" {
+		return errResp
+	}
+
+	obj = Object{
+		"HOST": {host},
+	}
+	applyArray(obj, "IPADDRESS", cmd)
+	if len(obj["IPADDRESS"]) == 0 {
+		return ErrorResponse(CodeMissingAttribute, "Missing required attribute; IPADDRESS0")
+	}
+	s.nameservers[host] = obj
+
+	return SuccessResponse(nil)
+}
+
+func handleStatusNameserver(s *Server, cmd Command, _ string) *Response {
+	_, obj, errResp := s.lookupNameserver(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	return SuccessResponse(copyObject(obj))
+}
+
+func handleModifyNameserver(s *Server, cmd Command, _ string) *Response {
+	host, obj, errResp := s.lookupNameserver(cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	updated := copyObject(obj)
+	applyArray(updated, "IPADDRESS", cmd)
+	if len(updated["IPADDRESS"]) == 0 {
+		return ErrorResponse(CodeInvalidValue, "Invalid attribute value; a nameserver needs at least one IPADDRESS")
+	}
+	s.nameservers[host] = updated
+
+	return SuccessResponse(nil)
+}
+
+func handleDeleteNameserver(s *Server, cmd Command, _ string) *Response {
+	host, _, errResp := s.lookupNameserver(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	delete(s.nameservers, host)
+	return SuccessResponse(nil)
+}
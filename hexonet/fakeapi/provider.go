@@ -0,0 +1,15 @@
+package fakeapi
+
+import (
+	"github.com/Doridian/terraform-provider-hexonet/hexonet"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
+)
+
+// ClientFactory returns a factory for hexonet.NewWithClientFactory which points the SDK client at this server
+func (s *Server) ClientFactory() hexonet.ClientFactory {
+	return func(cfg *hexonet.ClientConfig) hexonet.Client {
+		c := apiclient.NewAPIClient()
+		c.SetURL(s.URL())
+		return hexonet.NewSDKClient(c, cfg)
+	}
+}
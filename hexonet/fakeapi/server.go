@@ -0,0 +1,392 @@
+// Package fakeapi provides an in-process fake of the Hexonet plain-text API for offline testing
+package fakeapi
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/responseparser"
+)
+
+// Response codes used by the fake, matching the ones the real API uses
+const (
+	CodeSuccess            = 200
+	CodeTemporaryError     = 420
+	CodeInvalidCommand     = 500
+	CodeMissingAttribute   = 504
+	CodeInvalidSyntax      = 505
+	CodeAuthFailed         = 530
+	CodeAuthorizationError = 531
+	CodeObjectExists       = 540
+	CodeInvalidValue       = 541
+	CodeObjectNotFound     = 545
+	CodeCommandFailed      = 549
+)
+
+// Object is the property representation of an API object (column name => values)
+type Object = map[string][]string
+
+// Command is a single flattened API command (parameter name => value)
+type Command = map[string]string
+
+// Response is what a Handler returns, it is serialized into the plain-text response format
+type Response struct {
+	Code        int
+	Description string
+	Properties  Object
+}
+
+// Handler processes a single authenticated command, the server lock is held while it runs
+type Handler = func(s *Server, cmd Command, session string) *Response
+
+// Server is a fake Hexonet API endpoint keeping all state in memory
+type Server struct {
+	Username string
+	Password string
+	// If set, logins also need the current TOTP code of this secret
+	TOTPSecret string
+	// Number of StatusDomainTransfer calls a transfer stays pending for, 0 completes transfers right away
+	TransferPolls int
+
+	httpServer *httptest.Server
+
+	lock     sync.Mutex
+	handlers map[string]Handler
+	sessions map[string]bool
+	domains  map[string]Object
+	contacts map[string]Object
+	// Domains registered elsewhere (name => auth code), see SetForeignDomain
+	foreignDomains map[string]string
+	// Reasons foreign domains' transfers get rejected for, see RejectTransfer
+	rejectedTransfers map[string]string
+	pendingTransfers  map[string]*pendingTransfer
+	premiumDomains    map[string]*premiumDomain
+	// Price relations of the user, see SetAccountPrice
+	priceRelations map[string]string
+	nameservers    map[string]Object
+	commandLog     []Command
+	lastUserAgent  string
+	failures       map[string]*injectedFailure
+}
+
+type injectedFailure struct {
+	remaining  int
+	code       int
+	afterApply bool
+}
+
+// NewServer starts a new fake API server accepting the given credentials
+func NewServer(username string, password string) *Server {
+	s := &Server{
+		Username:          username,
+		Password:          password,
+		handlers:          make(map[string]Handler),
+		sessions:          make(map[string]bool),
+		domains:           make(map[string]Object),
+		foreignDomains:    make(map[string]string),
+		rejectedTransfers: make(map[string]string),
+		pendingTransfers:  make(map[string]*pendingTransfer),
+		premiumDomains:    make(map[string]*premiumDomain),
+		priceRelations:    make(map[string]string),
+		contacts:          make(map[string]Object),
+		nameservers:       make(map[string]Object),
+		failures:          make(map[string]*injectedFailure),
+	}
+
+	s.Handle("EndSession", handleEndSession)
+	s.Handle("StatusAccount", handleStatusAccount)
+	s.Handle("StatusUser", handleStatusUser)
+
+	s.Handle("CheckDomains", handleCheckDomains)
+	s.Handle("AddDomain", handleAddDomain)
+	s.Handle("StatusDomain", handleStatusDomain)
+	s.Handle("ModifyDomain", handleModifyDomain)
+	s.Handle("DeleteDomain", handleDeleteDomain)
+	s.Handle("TransferDomain", handleTransferDomain)
+	s.Handle("StatusDomainTransfer", handleStatusDomainTransfer)
+	s.Handle("SetDomainRenewalMode", handleSetDomainRenewalMode)
+	s.Handle("PushDomain", handlePushDomain)
+	s.Handle("RenewDomain", handleRenewDomain)
+
+	s.Handle("AddContact", handleAddContact)
+	s.Handle("StatusContact", handleStatusContact)
+	s.Handle("ModifyContact", handleModifyContact)
+	s.Handle("DeleteContact", handleDeleteContact)
+
+	s.Handle("AddNameserver", handleAddNameserver)
+	s.Handle("StatusNameserver", handleStatusNameserver)
+	s.Handle("ModifyNameserver", handleModifyNameserver)
+	s.Handle("DeleteNameserver", handleDeleteNameserver)
+
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the API call URL of the server
+func (s *Server) URL() string {
+	return s.httpServer.URL + "/api/call.cgi"
+}
+
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Handle registers (or replaces) the handler for a command, command names are case-insensitive
+func (s *Server) Handle(command string, handler Handler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[strings.ToUpper(command)] = handler
+}
+
+// InjectFailure makes the next n calls of a command fail with the given code
+// With afterApply the command is still executed, as when the connection drops after the API processed it
+func (s *Server) InjectFailure(command string, n int, code int, afterApply bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures[strings.ToUpper(command)] = &injectedFailure{
+		remaining:  n,
+		code:       code,
+		afterApply: afterApply,
+	}
+}
+
+// CommandLog returns all commands received so far (except session handling)
+func (s *Server) CommandLog() []Command {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Command{}, s.commandLog...)
+}
+
+// LastUserAgent returns the User-Agent header of the last request received
+func (s *Server) LastUserAgent() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lastUserAgent
+}
+
+// SessionCount returns the number of currently open sessions
+func (s *Server) SessionCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.sessions)
+}
+
+// ExpireSessions ends all open sessions, as the API does after a period of inactivity
+func (s *Server) ExpireSessions() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sessions = make(map[string]bool)
+}
+
+// Domain returns a copy of the stored domain object or nil
+func (s *Server) Domain(name string) Object {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return copyObject(s.domains[strings.ToLower(name)])
+}
+
+// SetDomain creates or replaces a domain object (for seeding domains that "already exist" in the account)
+func (s *Server) SetDomain(name string, obj Object) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj = copyObject(obj)
+	obj["ID"] = []string{strings.ToLower(name)}
+	s.domains[strings.ToLower(name)] = obj
+}
+
+// Contact returns a copy of the stored contact object or nil
+func (s *Server) Contact(id string) Object {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return copyObject(s.contacts[strings.ToUpper(id)])
+}
+
+// DeleteContact removes a contact behind the provider's back (simulating out-of-band changes)
+func (s *Server) DeleteContact(id string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.contacts, strings.ToUpper(id))
+}
+
+// Nameserver returns a copy of the stored nameserver object or nil
+func (s *Server) Nameserver(host string) Object {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return copyObject(s.nameservers[strings.ToLower(host)])
+}
+
+// DeleteNameserver removes a nameserver behind the provider's back (simulating out-of-band changes)
+func (s *Server) DeleteNameserver(host string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.nameservers, strings.ToLower(host))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cmd := parseCommand(r.PostForm.Get("s_command"))
+
+	s.lock.Lock()
+	s.lastUserAgent = r.UserAgent()
+	res := s.dispatch(r, cmd)
+	s.lock.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(res.serialize()))
+}
+
+func (s *Server) dispatch(r *http.Request, cmd Command) *Response {
+	command := strings.ToUpper(cmd["COMMAND"])
+	if command == "STARTSESSION" {
+		return s.startSession(r)
+	}
+
+	handler := s.handlers[command]
+	if handler == nil {
+		return ErrorResponse(CodeInvalidCommand, "Invalid command name")
+	}
+
+	if !s.authenticated(r) {
+		return ErrorResponse(CodeAuthFailed, "Authentication failed; SESSION NOT FOUND")
+	}
+
+	if command != "ENDSESSION" {
+		s.commandLog = append(s.commandLog, cmd)
+	}
+
+	failure := s.failures[command]
+	if failure == nil || failure.remaining <= 0 {
+		return handler(s, cmd, r.PostForm.Get("s_session"))
+	}
+
+	failure.remaining--
+	if failure.afterApply {
+		_ = handler(s, cmd, r.PostForm.Get("s_session"))
+	}
+	return ErrorResponse(failure.code, "Injected failure")
+}
+
+func (s *Server) startSession(r *http.Request) *Response {
+	if !s.checkLogin(r.PostForm.Get("s_login"), r.PostForm.Get("s_pw")) {
+		return ErrorResponse(CodeAuthFailed, "Authentication failed")
+	}
+	if !s.checkOTP(r.PostForm.Get("s_otp")) {
+		return ErrorResponse(CodeAuthFailed, "Authentication failed; invalid OTP")
+	}
+
+	session := randomID("")
+	s.sessions[session] = true
+	return SuccessResponse(Object{
+		"SESSION": {session},
+	})
+}
+
+func (s *Server) authenticated(r *http.Request) bool {
+	session := r.PostForm.Get("s_session")
+	if session != "" {
+		return s.sessions[session]
+	}
+	return s.checkLogin(r.PostForm.Get("s_login"), r.PostForm.Get("s_pw"))
+}
+
+func (s *Server) checkLogin(login string, password string) bool {
+	// Role users log in as "user!role", the role is accepted as long as the account matches
+	user, _, _ := strings.Cut(login, "!")
+	return user == s.Username && password == s.Password
+}
+
+func (s *Server) checkOTP(otp string) bool {
+	if s.TOTPSecret == "" {
+		return otp == ""
+	}
+	// Also accept the previous code, in case the login crossed a period boundary
+	now := time.Now()
+	for _, t := range []time.Time{now, now.Add(-utils.TOTP_PERIOD)} {
+		expected, err := utils.GenerateTOTP(s.TOTPSecret, t)
+		if err == nil && otp == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCommand(raw string) Command {
+	cmd := make(Command)
+	for _, line := range strings.Split(raw, "\n") {
+		key, val, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		cmd[strings.ToUpper(key)] = val
+	}
+	return cmd
+}
+
+// SuccessResponse returns a 200 response with the given properties
+func SuccessResponse(props Object) *Response {
+	return &Response{
+		Code:        CodeSuccess,
+		Description: "Command completed successfully",
+		Properties:  props,
+	}
+}
+
+// ErrorResponse returns a response with the given code and description and no properties
+func ErrorResponse(code int, description string) *Response {
+	return &Response{
+		Code:        code,
+		Description: description,
+	}
+}
+
+func (r *Response) serialize() string {
+	hash := map[string]interface{}{
+		"CODE":        fmt.Sprintf("%d", r.Code),
+		"DESCRIPTION": r.Description,
+	}
+	if len(r.Properties) > 0 {
+		hash["PROPERTY"] = map[string][]string(r.Properties)
+	}
+	return responseparser.Serialize(hash)
+}
+
+func handleEndSession(s *Server, cmd Command, session string) *Response {
+	delete(s.sessions, session)
+	return SuccessResponse(nil)
+}
+
+func copyObject(obj Object) Object {
+	if obj == nil {
+		return nil
+	}
+	res := make(Object, len(obj))
+	for k, v := range obj {
+		res[k] = append([]string{}, v...)
+	}
+	return res
+}
+
+func randomID(prefix string) string {
+	const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for i := 0; i < 8; i++ {
+		sb.WriteByte(chars[rand.Intn(len(chars))])
+	}
+	return sb.String()
+}
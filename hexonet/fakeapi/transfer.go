@@ -0,0 +1,110 @@
+package fakeapi
+
+import (
+	"strings"
+)
+
+type pendingTransfer struct {
+	remaining int
+	rejection string
+	// Set once a rejected transfer stopped being pending, it is reported until the next transfer request
+	failed bool
+	obj    Object
+}
+
+// SetForeignDomain registers a domain at "another registrar", so it can be transferred in with the given auth code
+func (s *Server) SetForeignDomain(name string, authCode string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.foreignDomains[strings.ToLower(name)] = authCode
+}
+
+// RejectTransfer makes transfers of a foreign domain fail with the given reason once they stop being pending
+// An empty reason lets the next transfer request succeed again
+func (s *Server) RejectTransfer(name string, reason string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.rejectedTransfers[strings.ToLower(name)] = reason
+}
+
+func handleTransferDomain(s *Server, cmd Command, _ string) *Response {
+	name, obj, _ := s.lookupDomain(cmd)
+	if name == "" {
+		return ErrorResponse(CodeMissingAttribute, "Missing required attribute; DOMAIN")
+	}
+	if obj != nil {
+		return ErrorResponse(CodeObjectExists, "Object exists; domain is already in this account")
+	}
+	if transfer := s.pendingTransfers[name]; transfer != nil && !transfer.failed {
+		return ErrorResponse(CodeObjectExists, "Object exists; transfer is already pending")
+	}
+	if !strings.EqualFold(cmd["ACTION"], "REQUEST") {
+		return ErrorResponse(CodeInvalidSyntax, "Invalid attribute value; ACTION")
+	}
+
+	authCode, found := s.foreignDomains[name]
+	if !found {
+		return ErrorResponse(CodeCommandFailed, "Command failed; domain is not registered")
+	}
+	if cmd["AUTH"] == "" {
+		return ErrorResponse(CodeMissingAttribute, "Missing required attribute; AUTH")
+	}
+	if cmd["AUTH"] != authCode {
+		return ErrorResponse(CodeAuthorizationError, "Authorization failed; invalid auth code")
+	}
+
+	obj = newDomainObject(name, 1)
+	applyDomainParams(obj, cmd)
+	transfer := &pendingTransfer{
+		remaining: s.TransferPolls,
+		rejection: s.rejectedTransfers[name],
+		obj:       obj,
+	}
+
+	if transfer.remaining <= 0 && transfer.rejection == "" {
+		// Replaces a failed earlier transfer
+		delete(s.pendingTransfers, name)
+		s.completeTransfer(name, transfer)
+		return SuccessResponse(nil)
+	}
+	s.pendingTransfers[name] = transfer
+	return SuccessResponse(nil)
+}
+
+// Reports PENDING while the transfer runs, FAILED for a rejected transfer until it is requested again and 545 when there is no transfer (anymore)
+func handleStatusDomainTransfer(s *Server, cmd Command, _ string) *Response {
+	name := strings.ToLower(cmd["DOMAIN"])
+	if name == "" {
+		return ErrorResponse(CodeMissingAttribute, "Missing required attribute; DOMAIN")
+	}
+	transfer := s.pendingTransfers[name]
+	if transfer == nil {
+		return ErrorResponse(CodeObjectNotFound, "Object does not exist")
+	}
+
+	if transfer.remaining > 0 {
+		transfer.remaining--
+		return SuccessResponse(Object{
+			"DOMAIN":         {name},
+			"TRANSFERSTATUS": {"PENDING"},
+		})
+	}
+
+	if transfer.rejection != "" {
+		transfer.failed = true
+		return SuccessResponse(Object{
+			"DOMAIN":         {name},
+			"TRANSFERSTATUS": {"FAILED"},
+			"TRANSFERLOG":    {transfer.rejection},
+		})
+	}
+
+	delete(s.pendingTransfers, name)
+	s.completeTransfer(name, transfer)
+	return ErrorResponse(CodeObjectNotFound, "Object does not exist")
+}
+
+func (s *Server) completeTransfer(name string, transfer *pendingTransfer) {
+	delete(s.foreignDomains, name)
+	s.domains[name] = transfer.obj
+}
@@ -3,37 +3,77 @@ package hexonet
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
-	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
 	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const DEFAULT_OPERATION_TIMEOUT = 20 * time.Minute
+const DEFAULT_READ_TIMEOUT = 5 * time.Minute
+
 func New() provider.Provider {
-	return &localProvider{}
+	return &localProvider{
+		clientFactory: newSDKClient,
+	}
+}
+
+// NewWithClientFactory returns a provider constructor which uses the given factory instead of the rtldev SDK to talk to the API
+func NewWithClientFactory(clientFactory ClientFactory) func() provider.Provider {
+	return func() provider.Provider {
+		return &localProvider{
+			clientFactory: clientFactory,
+		}
+	}
 }
 
 type localProviderData struct {
-	Username                types.String `tfsdk:"username"`
-	Role                    types.String `tfsdk:"role"`
-	Password                types.String `tfsdk:"password"`
-	MfaToken                types.String `tfsdk:"mfa_token"`
-	Live                    types.Bool   `tfsdk:"live"`
-	HighPerformance         types.Bool   `tfsdk:"high_performance"`
-	AllowDomainCreateDelete types.Bool   `tfsdk:"allow_domain_create_delete"`
+	Username                types.String          `tfsdk:"username"`
+	Role                    types.String          `tfsdk:"role"`
+	Password                types.String          `tfsdk:"password"`
+	MfaToken                types.String          `tfsdk:"mfa_token"`
+	MfaTotpSecret           types.String          `tfsdk:"mfa_totp_secret"`
+	Live                    types.Bool            `tfsdk:"live"`
+	HighPerformance         types.Bool            `tfsdk:"high_performance"`
+	AllowDomainCreateDelete types.Bool            `tfsdk:"allow_domain_create_delete"`
+	MaxRetries              types.Int64           `tfsdk:"max_retries"`
+	MinBackoff              types.String          `tfsdk:"min_backoff"`
+	MaxBackoff              types.String          `tfsdk:"max_backoff"`
+	SessionCacheFile        types.String          `tfsdk:"session_cache_file"`
+	CredentialsFile         types.String          `tfsdk:"credentials_file"`
+	Profile                 types.String          `tfsdk:"profile"`
+	CredentialProcess       types.String          `tfsdk:"credential_process"`
+	EndpointURL             types.String          `tfsdk:"endpoint_url"`
+	ProxyURL                types.String          `tfsdk:"proxy_url"`
+	UserAgentSuffix         types.String          `tfsdk:"user_agent_suffix"`
+	CABundleFile            types.String          `tfsdk:"ca_bundle_file"`
+	ReadOnly                types.Bool            `tfsdk:"read_only"`
+	ExpectedAccount         types.String          `tfsdk:"expected_account"`
+	ExpiryWarningDays       types.Int64           `tfsdk:"expiry_warning_days"`
+	ExpiryErrorDays         types.Int64           `tfsdk:"expiry_error_days"`
+	AccountRenewalMode      types.String          `tfsdk:"account_renewal_mode"`
+	DomainProtection        *domainProtectionData `tfsdk:"domain_protection"`
 }
 
 type localProvider struct {
 	allowDomainCreateDelete bool
+	domainProtection        *domainProtection
+	expiryThresholds        expiryThresholds
 	configured              bool
-	client                  *apiclient.APIClient
+	clientFactory           ClientFactory
+	client                  Client
 }
 
 func envVarForKey(key string) string {
@@ -63,7 +103,12 @@ func (p *localProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 			"mfa_token": schema.StringAttribute{
 				Sensitive:   true,
 				Optional:    true,
-				Description: envDescription("MFA token (required if MFA is enabled)", "mfa_token"),
+				Description: envDescription("MFA token (required if MFA is enabled, unless mfa_totp_secret is set)", "mfa_token"),
+			},
+			"mfa_totp_secret": schema.StringAttribute{
+				Sensitive:   true,
+				Optional:    true,
+				Description: envDescription("Base32 TOTP secret of the MFA setup, the current MFA token is generated from it on every login (alternative to mfa_token)", "mfa_totp_secret"),
 			},
 			"live": schema.BoolAttribute{
 				Optional:    true,
@@ -77,6 +122,85 @@ func (p *localProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Required:    true,
 				Description: "Whether to use AddDomain / DeleteDomain to send domain registration/deletion requests, otherwise will only read and update domains, never register or delete (extreme caution should be taken when enabling this option!)",
 			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: envDescription("Whether to block every command that could change anything (Add/Modify/Delete/...) before it is sent to the API, only reading objects works (default: false)", "read_only"),
+			},
+			"expected_account": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("Account the provider must be logged into, otherwise configuring it fails (format: user[!role][@live|@ote], example: myuser@ote, account IDs are not supported as the API only reports the user), with a system endpoint_url must be left unset or point to that system", "expected_account"),
+			},
+			"expiry_warning_days": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "Warn about domains expiring within this many days unless their renewal mode is AUTORENEW or RENEWONCE (default: 0, disabled), can be overridden per domain",
+			},
+			"expiry_error_days": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "Like expiry_warning_days, but fails the plan (default: 0, disabled), can be overridden per domain",
+			},
+			"account_renewal_mode": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(RENEWAL_MODE_AUTORENEW, RENEWAL_MODE_AUTOEXPIRE, RENEWAL_MODE_AUTODELETE),
+				},
+				Description: fmt.Sprintf("Renewal mode the account applies to domains with renewal mode %s, for expiry_warning_days and expiry_error_days (if not set, domains with %s are not checked)", RENEWAL_MODE_DEFAULT, RENEWAL_MODE_DEFAULT),
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: fmt.Sprintf("How often to retry requests failing with temporary or connection errors (default: %d), only Status/Modify commands and Add commands which can be verified not to have gone through are retried", DEFAULT_MAX_RETRIES),
+			},
+			"min_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Delay before the first retry as a duration (example: 500ms, default: %s), doubled for every further retry", DEFAULT_MIN_BACKOFF),
+			},
+			"max_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Maximum delay between retries as a duration (example: 1m, default: %s)", DEFAULT_MAX_BACKOFF),
+			},
+			"credentials_file": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("INI or JSON file with named profiles containing username, role, password and mfa_totp_secret, used for values not set otherwise", "credentials_file"),
+			},
+			"profile": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription(fmt.Sprintf("Profile to use from credentials_file (default: %s)", DEFAULT_CREDENTIALS_PROFILE), "profile"),
+			},
+			"credential_process": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("Command printing a JSON object with username, role, password and mfa_totp_secret on stdout, used for values not set otherwise (takes precedence over credentials_file)", "credential_process"),
+			},
+			"endpoint_url": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("API URL to send requests to instead of the one selected by live and high_performance (example: https://api.example.com/api/call.cgi)", "endpoint_url"),
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("HTTP(S) proxy to send API requests through (example: http://proxy.example.com:3128)", "proxy_url"),
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("Text appended to the User-Agent header of API requests", "user_agent_suffix"),
+			},
+			"ca_bundle_file": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("PEM file with additional CA certificates to trust for API requests (for TLS intercepting proxies)", "ca_bundle_file"),
+			},
+			"session_cache_file": schema.StringAttribute{
+				Optional:    true,
+				Description: envDescription("File to keep the API session in across runs, so not every run needs a new login (and MFA token), created with mode 0600 and refused if accessible by others", "session_cache_file"),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"domain_protection": makeDomainProtectionSchema(),
 		},
 		Description: "Provider for Hexonet domain API",
 	}
@@ -90,6 +214,7 @@ func (p *localProvider) Resources(_ context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		newResourceContact,
 		newResourceDomain,
+		newResourceDomainRenewal,
 		newResourceNameServer,
 	}
 }
@@ -98,6 +223,7 @@ func (p *localProvider) DataSources(_ context.Context) []func() datasource.DataS
 	return []func() datasource.DataSource{
 		newDataSourceContact,
 		newDataSourceDomain,
+		newDataSourceDomainCheck,
 		newDataSourceNameServer,
 	}
 }
@@ -121,6 +247,123 @@ func getValueOrDefaultToEnv(val types.String, key string, resp *provider.Configu
 	return res
 }
 
+// Like getValueOrDefaultToEnv, but falls back to the value from credentials_file / credential_process last
+func getValueOrDefaultToCredentials(val types.String, key string, credValue string, resp *provider.ConfigureResponse, allowEmpty bool) string {
+	res := getValueOrDefaultToEnv(val, key, resp, true)
+	if res == "" {
+		res = credValue
+	}
+
+	if res == "" && !allowEmpty {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Empty value for %s", key))
+	}
+	return res
+}
+
+func loadExternalCredentials(ctx context.Context, config *localProviderData, resp *provider.ConfigureResponse) *credentialSet {
+	creds := &credentialSet{}
+
+	credentialProcess := getValueOrDefaultToEnv(config.CredentialProcess, "credential_process", resp, true)
+	if credentialProcess != "" {
+		processCreds, err := runCredentialProcess(ctx, credentialProcess)
+		if err != nil {
+			resp.Diagnostics.AddError("Can not configure client", err.Error())
+			return creds
+		}
+		creds.merge(processCreds)
+	}
+
+	credentialsFile := getValueOrDefaultToEnv(config.CredentialsFile, "credentials_file", resp, true)
+	profile := getValueOrDefaultToEnv(config.Profile, "profile", resp, true)
+	if profile == "" {
+		profile = DEFAULT_CREDENTIALS_PROFILE
+	}
+	if credentialsFile != "" {
+		fileCreds, err := loadCredentialsFile(credentialsFile, profile)
+		if err != nil {
+			resp.Diagnostics.AddError("Can not configure client", err.Error())
+			return creds
+		}
+		creds.merge(fileCreds)
+	}
+
+	return creds
+}
+
+func getBoolOrDefaultToEnv(val types.Bool, key string, def bool, resp *provider.ConfigureResponse) bool {
+	if val.IsUnknown() {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Unknown value for %s", key))
+		return def
+	}
+
+	if !val.IsNull() {
+		return val.ValueBool()
+	}
+
+	env := os.Getenv(envVarForKey(key))
+	if env == "" {
+		return def
+	}
+	res, err := strconv.ParseBool(env)
+	if err != nil {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Invalid boolean in %s: %s", envVarForKey(key), env))
+		return def
+	}
+	return res
+}
+
+func getURLOrDefaultToEnv(val types.String, key string, resp *provider.ConfigureResponse) string {
+	res := getValueOrDefaultToEnv(val, key, resp, true)
+	if res == "" {
+		return ""
+	}
+
+	u, err := url.Parse(res)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Invalid URL for %s, expected http(s)://host[:port][/path]", key))
+		return ""
+	}
+	return res
+}
+
+func getDurationOrDefault(val types.String, key string, def time.Duration, resp *provider.ConfigureResponse) time.Duration {
+	if val.IsUnknown() {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Unknown value for %s", key))
+		return def
+	}
+
+	if val.IsNull() {
+		return def
+	}
+
+	res, err := time.ParseDuration(val.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Invalid duration for %s: %s", key, err.Error()))
+		return def
+	}
+	if res <= 0 {
+		resp.Diagnostics.AddError("Can not configure client", fmt.Sprintf("Duration for %s must be positive", key))
+		return def
+	}
+	return res
+}
+
+// Login takes the MFA token as its only optional parameter, so it must not be passed at all without MFA
+func makeLoginParams(mfaToken string, mfaTotpSecret string, now time.Time) ([]string, error) {
+	if mfaTotpSecret != "" {
+		token, err := utils.GenerateTOTP(mfaTotpSecret, now)
+		if err != nil {
+			return nil, err
+		}
+		return []string{token}, nil
+	}
+
+	if mfaToken != "" {
+		return []string{mfaToken}, nil
+	}
+	return []string{}, nil
+}
+
 func (p *localProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	resp.DataSourceData = p
 	resp.ResourceData = p
@@ -138,10 +381,41 @@ func (p *localProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		p.allowDomainCreateDelete = false
 	}
 
-	username := getValueOrDefaultToEnv(config.Username, "username", resp, false)
-	password := getValueOrDefaultToEnv(config.Password, "password", resp, false)
-	role := getValueOrDefaultToEnv(config.Role, "role", resp, true)
+	p.domainProtection = makeDomainProtection(ctx, config.DomainProtection, &resp.Diagnostics)
+	p.expiryThresholds = expiryThresholds{
+		accountRenewalMode: config.AccountRenewalMode.ValueString(),
+	}.override(config.ExpiryWarningDays, config.ExpiryErrorDays)
+
+	creds := loadExternalCredentials(ctx, &config, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	username := getValueOrDefaultToCredentials(config.Username, "username", creds.Username, resp, false)
+	password := getValueOrDefaultToCredentials(config.Password, "password", creds.Password, resp, false)
+	role := getValueOrDefaultToCredentials(config.Role, "role", creds.Role, resp, true)
 	mfaToken := getValueOrDefaultToEnv(config.MfaToken, "mfa_token", resp, true)
+	mfaTotpSecret := getValueOrDefaultToCredentials(config.MfaTotpSecret, "mfa_totp_secret", creds.MfaTotpSecret, resp, true)
+	sessionCacheFile := getValueOrDefaultToEnv(config.SessionCacheFile, "session_cache_file", resp, true)
+	endpointURL := getURLOrDefaultToEnv(config.EndpointURL, "endpoint_url", resp)
+	proxyURL := getURLOrDefaultToEnv(config.ProxyURL, "proxy_url", resp)
+	userAgentSuffix := getValueOrDefaultToEnv(config.UserAgentSuffix, "user_agent_suffix", resp, true)
+	caBundleFile := getValueOrDefaultToEnv(config.CABundleFile, "ca_bundle_file", resp, true)
+	readOnly := getBoolOrDefaultToEnv(config.ReadOnly, "read_only", false, resp)
+
+	var expected *expectedAccount
+	expectedAccountVal := getValueOrDefaultToEnv(config.ExpectedAccount, "expected_account", resp, true)
+	if expectedAccountVal != "" {
+		var err error
+		expected, err = parseExpectedAccount(expectedAccountVal)
+		if err != nil {
+			resp.Diagnostics.AddError("Can not configure client", err.Error())
+		}
+	}
+
+	if mfaToken != "" && mfaTotpSecret != "" {
+		resp.Diagnostics.AddError("Can not configure client", "Only one of mfa_token and mfa_totp_secret can be set")
+	}
 
 	highPerformance := false
 	live := true
@@ -154,42 +428,95 @@ func (p *localProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		live = config.Live.ValueBool()
 	}
 
+	retryPolicy := RetryPolicy{
+		MaxRetries: DEFAULT_MAX_RETRIES,
+		MinBackoff: getDurationOrDefault(config.MinBackoff, "min_backoff", DEFAULT_MIN_BACKOFF, resp),
+		MaxBackoff: getDurationOrDefault(config.MaxBackoff, "max_backoff", DEFAULT_MAX_BACKOFF, resp),
+	}
+
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		retryPolicy.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if retryPolicy.MinBackoff > retryPolicy.MaxBackoff {
+		resp.Diagnostics.AddError("Can not configure client", "min_backoff must not be larger than max_backoff")
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	c := apiclient.NewAPIClient()
-	if live {
-		c.UseLIVESystem()
-	} else {
-		c.UseOTESystem()
+	clientConfig := &ClientConfig{
+		Username:        username,
+		Role:            role,
+		Password:        password,
+		Live:            live,
+		HighPerformance: highPerformance,
+		EndpointURL:     endpointURL,
+		ProxyURL:        proxyURL,
+		UserAgentSuffix: userAgentSuffix,
 	}
 
-	if highPerformance {
-		c.UseHighPerformanceConnectionSetup()
-	} else {
-		c.UseDefaultConnectionSetup()
+	if caBundleFile != "" || userAgentSuffix != "" {
+		transport, err := makeTransport(caBundleFile, proxyURL)
+		if err != nil {
+			resp.Diagnostics.AddError("Can not configure client", err.Error())
+			return
+		}
+		clientConfig.Transport = transport
 	}
 
-	if role != "" {
-		c.SetRoleCredentials(username, role, password)
-	} else {
-		c.SetCredentials(username, password)
+	if expected != nil {
+		expected.checkConfig(clientConfig, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	var res *response.Response
-	if mfaToken != "" {
-		res = c.Login()
-	} else {
-		res = c.Login(mfaToken)
+	c := p.clientFactory(clientConfig)
+
+	if _, err := makeLoginParams(mfaToken, mfaTotpSecret, time.Now()); err != nil {
+		resp.Diagnostics.AddError("Can not configure client", err.Error())
+		return
 	}
 
-	utils.HandlePossibleErrorResponse(res, &resp.Diagnostics)
+	login := func() *response.Response {
+		// A TOTP secret needs a fresh token for every login
+		loginParams, _ := makeLoginParams(mfaToken, mfaTotpSecret, time.Now())
+		return c.Login(loginParams...)
+	}
+
+	var cache *sessionCache
+	if sessionCacheFile != "" {
+		cache = newSessionCache(sessionCacheFile, clientConfig)
+	}
+
+	sc := newSessionClient(c, login, cache)
+	res, err := sc.start()
+	if err != nil {
+		resp.Diagnostics.AddWarning("Could not use session cache", err.Error())
+	}
+	utils.HandlePossibleErrorResponse(ctx, res, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	p.client = c
+	if expected != nil {
+		expected.checkLoggedIn(ctx, sc, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			sc.Logout()
+			return
+		}
+	}
+
+	if p.client != nil {
+		// Configured again, do not leave the old session behind
+		p.client.Logout()
+	}
+	p.client = newRetryingClient(sc, retryPolicy)
+	if readOnly {
+		p.client = &readOnlyClient{Client: p.client}
+	}
 	p.configured = true
 }
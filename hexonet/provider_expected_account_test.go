@@ -0,0 +1,31 @@
+package hexonet_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccExpectedAccount(t *testing.T) {
+	fake := newFakeServer(t)
+	const dataSourceConfig = `
+data "hexonet_domain_check" "test" {
+  domains = ["example.com"]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig(false, `expected_account = "other.user"`) + dataSourceConfig,
+				ExpectError: regexp.MustCompile(`expected_account requires user "other.user", but the provider is logged\s+in\s+as\s+"test.user"`),
+			},
+			{
+				Config: providerConfig(false, `expected_account = "TEST.USER"`) + dataSourceConfig,
+				Check:  resource.TestCheckResourceAttr("data.hexonet_domain_check.test", "results.example.com.available", "true"),
+			},
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package hexonet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMakeLoginParams(t *testing.T) {
+	// The RFC 6238 SHA-1 test vector at T=59
+	now := time.Unix(59, 0)
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []struct {
+		name          string
+		mfaToken      string
+		mfaTotpSecret string
+		expected      []string
+		expectErr     bool
+	}{
+		{name: "no MFA", expected: []string{}},
+		{name: "token", mfaToken: "123456", expected: []string{"123456"}},
+		{name: "TOTP secret", mfaTotpSecret: secret, expected: []string{"287082"}},
+		{name: "invalid TOTP secret", mfaTotpSecret: "not-base32!", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := makeLoginParams(tt.mfaToken, tt.mfaTotpSecret, now)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, params)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet"
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// The acceptance tests run against the in-process fake API, so they need TF_ACC and a Terraform binary but no account
+const testUsername = "test.user"
+const testPassword = "test.password"
+
+func newFakeServer(t *testing.T) *fakeapi.Server {
+	fake := fakeapi.NewServer(testUsername, testPassword)
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func protoV6ProviderFactories(fake *fakeapi.Server) map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"hexonet": providerserver.NewProtocol6WithError(hexonet.NewWithClientFactory(fake.ClientFactory())()),
+	}
+}
+
+// Client talking to the fake API directly, for testing the client wrappers without Terraform
+func newFakeClient(t *testing.T, fake *fakeapi.Server) hexonet.Client {
+	c := fake.ClientFactory()(&hexonet.ClientConfig{
+		Username: testUsername,
+		Password: testPassword,
+	})
+	if resp := c.Login(); !resp.IsSuccess() {
+		t.Fatalf("login failed: %s", resp.GetDescription())
+	}
+	return c
+}
+
+// Provider block logging into the fake API, extra is added to the block as is
+func providerConfig(allowDomainCreateDelete bool, extra string) string {
+	return fmt.Sprintf(`
+provider "hexonet" {
+  username                   = %q
+  password                   = %q
+  allow_domain_create_delete = %t
+  %s
+}
+`, testUsername, testPassword, allowDomainCreateDelete, extra)
+}
+
+// Returns the commands of the given name received by the fake so far
+func commandsNamed(fake *fakeapi.Server, name string) []fakeapi.Command {
+	res := make([]fakeapi.Command, 0)
+	for _, cmd := range fake.CommandLog() {
+		if strings.EqualFold(cmd["COMMAND"], name) {
+			res = append(res, cmd)
+		}
+	}
+	return res
+}
+
+func lastCommandNamed(fake *fakeapi.Server, name string) (fakeapi.Command, error) {
+	cmds := commandsNamed(fake, name)
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("no %s command was sent", name)
+	}
+	return cmds[len(cmds)-1], nil
+}
+
+func testCheckCommandCount(fake *fakeapi.Server, name string, expected int) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		if n := len(commandsNamed(fake, name)); n != expected {
+			return fmt.Errorf("expected %d %s commands, got %d", expected, name, n)
+		}
+		return nil
+	}
+}
+
+// Checks the parameters of the last command of the given name, an empty value means the parameter must not be sent
+func testCheckLastCommand(fake *fakeapi.Server, name string, params map[string]string) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		cmd, err := lastCommandNamed(fake, name)
+		if err != nil {
+			return err
+		}
+		for k, v := range params {
+			actual, found := cmd[k]
+			if v == "" && found {
+				return fmt.Errorf("%s must not send %s, but sent %q", name, k, actual)
+			}
+			if v != "" && actual != v {
+				return fmt.Errorf("%s must send %s=%q, but sent %q", name, k, v, actual)
+			}
+		}
+		return nil
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
+type resourceContactData struct {
+	Contact
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
 type resourceContact struct {
 	p *localProvider
 }
@@ -17,8 +24,16 @@ func newResourceContact() resource.Resource {
 	return &resourceContact{}
 }
 
-func (r *resourceContact) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *resourceContact) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 		Attributes:  makeContactResourceSchema(),
 		Description: "Contact object, used for domain owner/admin/...",
 	}
@@ -41,19 +56,29 @@ func (r *resourceContact) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	data := &Contact{}
+	data := &resourceContactData{}
 	diags := req.Plan.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_ = makeContactCommand(r.p.client, utils.CommandCreate, data, &Contact{}, &resp.Diagnostics)
+	createTimeout, diags := data.Timeouts.Create(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindContactRead(data, r.p.client, &resp.Diagnostics)
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	res := makeContactCommand(ctx, r.p.client, utils.CommandCreate, &data.Contact, &Contact{}, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = utils.AutoBoxString(utils.ColumnFirstOrDefault(res, "CONTACT", nil))
+
+	data.Contact = *kindContactRead(ctx, &data.Contact, r.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -70,17 +95,27 @@ func (r *resourceContact) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	data := &Contact{}
+	data := &resourceContactData{}
 	diags := req.State.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindContactRead(data, r.p.client, &resp.Diagnostics)
+	readTimeout, diags := data.Timeouts.Read(ctx, DEFAULT_READ_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	readDiags := diag.Diagnostics{}
+	data.Contact = *kindContactRead(ctx, &data.Contact, r.p.client, &readDiags)
+	if utils.RemoveIfNotFound(ctx, readDiags, &resp.State, &resp.Diagnostics) {
+		return
+	}
 	diags = resp.State.Set(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -94,26 +129,35 @@ func (r *resourceContact) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	data := &Contact{}
+	data := &resourceContactData{}
 	diags := req.Plan.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	dataOld := &Contact{}
+	updateTimeout, diags := data.Timeouts.Update(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	dataOld := &resourceContactData{}
 	diags = req.State.Get(ctx, dataOld)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_ = makeContactCommand(r.p.client, utils.CommandUpdate, data, dataOld, &resp.Diagnostics)
+	_ = makeContactCommand(ctx, r.p.client, utils.CommandUpdate, &data.Contact, &dataOld.Contact, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindContactRead(data, r.p.client, &resp.Diagnostics)
+	data.Contact = *kindContactRead(ctx, &data.Contact, r.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -130,16 +174,25 @@ func (r *resourceContact) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	dataOld := &Contact{}
+	dataOld := &resourceContactData{}
 	diags := req.State.Get(ctx, dataOld)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_ = makeContactCommand(r.p.client, utils.CommandDelete, &Contact{
-		ID: dataOld.ID,
-	}, dataOld, &resp.Diagnostics)
+	deleteTimeout, diags := dataOld.Timeouts.Delete(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	_ = makeContactCommand(ctx, r.p.client, utils.CommandDelete, &Contact{
+		ID: dataOld.Contact.ID,
+	}, &dataOld.Contact, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -0,0 +1,185 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+const testContactRequired = `
+  first_name     = "Jane"
+  last_name      = "Doe"
+  address_line_1 = "1 Main Street"
+  city           = "Springfield"
+  zip            = "12345"
+  country        = "US"
+  phone          = "+1.5555555555"
+  email          = "jane@example.com"
+  disclose       = false
+`
+
+var regexpContactID = regexp.MustCompile(`^P-[A-Z0-9]{8}$`)
+
+func testContactConfig(optional string) string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_contact" "test" {
+  %s
+  %s
+}
+
+data "hexonet_contact" "test" {
+  id = hexonet_contact.test.id
+}
+`, testContactRequired, optional)
+}
+
+// Without the data source, which could not read the contact once it is removed
+func testContactOnlyConfig() string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_contact" "test" {
+  %s
+}
+`, testContactRequired)
+}
+
+func testCheckContactDestroyed(fake *fakeapi.Server) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "hexonet_contact" {
+				continue
+			}
+			if fake.Contact(rs.Primary.Attributes["id"]) != nil {
+				return fmt.Errorf("contact %s still exists", rs.Primary.Attributes["id"])
+			}
+		}
+		return nil
+	}
+}
+
+// Checks a field of the contact stored in the fake, an empty value means the field must not be set
+func testCheckFakeContactField(fake *fakeapi.Server, field string, expected string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		rs := s.RootModule().Resources["hexonet_contact.test"]
+		if rs == nil {
+			return fmt.Errorf("hexonet_contact.test not in state")
+		}
+		obj := fake.Contact(rs.Primary.Attributes["id"])
+		if obj == nil {
+			return fmt.Errorf("contact %s does not exist", rs.Primary.Attributes["id"])
+		}
+		actual := obj[field]
+		if expected == "" && len(actual) > 0 {
+			return fmt.Errorf("expected %s to be cleared, got %v", field, actual)
+		}
+		if expected != "" && (len(actual) != 1 || actual[0] != expected) {
+			return fmt.Errorf("expected %s %q, got %v", field, expected, actual)
+		}
+		return nil
+	}
+}
+
+func TestAccContact_basic(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckContactDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testContactConfig(`
+  title          = "Dr."
+  middle_name    = "Q."
+  organization   = "Example Inc."
+  address_line_2 = "Suite 100"
+  state          = "IL"
+  fax            = "+1.5555555556"
+  vat_id         = "US123"
+  extra_attributes = {
+    "FOO" = "bar"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The ID comes from the AddContact response
+					resource.TestMatchResourceAttr("hexonet_contact.test", "id", regexpContactID),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "title", "Dr."),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "middle_name", "Q."),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "organization", "Example Inc."),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "address_line_2", "Suite 100"),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "state", "IL"),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "fax", "+1.5555555556"),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "vat_id", "US123"),
+					resource.TestCheckResourceAttr("hexonet_contact.test", "extra_attributes.FOO", "bar"),
+					resource.TestCheckResourceAttrPair("data.hexonet_contact.test", "first_name", "hexonet_contact.test", "first_name"),
+					resource.TestCheckResourceAttrPair("data.hexonet_contact.test", "organization", "hexonet_contact.test", "organization"),
+					resource.TestCheckResourceAttrPair("data.hexonet_contact.test", "address_line_2", "hexonet_contact.test", "address_line_2"),
+					testCheckFakeContactField(fake, "ORGANIZATION", "Example Inc."),
+					testCheckFakeContactField(fake, "X-FOO", "bar"),
+					testCheckCommandCount(fake, "AddContact", 1),
+				),
+			},
+			{
+				ResourceName:      "hexonet_contact.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testContactConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "title"),
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "middle_name"),
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "organization"),
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "address_line_2"),
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "state"),
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "fax"),
+					resource.TestCheckNoResourceAttr("hexonet_contact.test", "vat_id"),
+					resource.TestCheckNoResourceAttr("data.hexonet_contact.test", "organization"),
+					// Optional fields are cleared using DELETE<n>
+					testCheckLastCommand(fake, "ModifyContact", map[string]string{
+						"DELETE0":      "TITLE",
+						"DELETE1":      "MIDDLENAME",
+						"DELETE2":      "ORGANIZATION",
+						"DELETE3":      "STATE",
+						"DELETE4":      "FAX",
+						"DELETE5":      "VATID",
+						"TITLE":        "",
+						"ORGANIZATION": "",
+					}),
+					testCheckFakeContactField(fake, "TITLE", ""),
+					testCheckFakeContactField(fake, "ORGANIZATION", ""),
+					testCheckFakeContactField(fake, "FIRSTNAME", "Jane"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccContact_removedOutsideTerraform(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckContactDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testContactOnlyConfig(),
+				Check: func(s *terraform.State) error {
+					fake.DeleteContact(s.RootModule().Resources["hexonet_contact.test"].Primary.Attributes["id"])
+					return nil
+				},
+				// Reading the missing contact removes it from the state, so it is planned to be created again
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testContactOnlyConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckFakeContactField(fake, "FIRSTNAME", "Jane"),
+					testCheckCommandCount(fake, "AddContact", 2),
+				),
+			},
+		},
+	})
+}
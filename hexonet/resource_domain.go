@@ -2,13 +2,119 @@ package hexonet
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
+const CREATE_MODE_ADOPT = "adopt"
+const CREATE_MODE_REGISTER = "register"
+const CREATE_MODE_TRANSFER = "transfer"
+
+const MAX_REGISTRATION_PERIOD = 10
+
+const ON_DESTROY_FORGET = "forget"
+const ON_DESTROY_DELETE = "delete"
+const ON_DESTROY_AUTOEXPIRE = "autoexpire"
+const ON_DESTROY_PUSH = "push"
+
+type resourceDomainData struct {
+	Domain
+	CreateMode         types.String   `tfsdk:"create_mode"`
+	TransferAuthCode   types.String   `tfsdk:"transfer_auth_code"`
+	RegistrationPeriod types.Int64    `tfsdk:"registration_period"`
+	AcceptPremiumPrice types.Object   `tfsdk:"accept_premium_price"`
+	OnDestroy          types.String   `tfsdk:"on_destroy"`
+	ExpiryWarningDays  types.Int64    `tfsdk:"expiry_warning_days"`
+	ExpiryErrorDays    types.Int64    `tfsdk:"expiry_error_days"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+type domainPremiumPriceData struct {
+	MaxPrice types.Float64 `tfsdk:"max_price"`
+	Currency types.String  `tfsdk:"currency"`
+}
+
+// Attributes only the resource has, as they control what Terraform does with the domain
+func makeDomainLifecycleSchema() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"create_mode": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(CREATE_MODE_ADOPT, CREATE_MODE_REGISTER, CREATE_MODE_TRANSFER),
+			},
+			Description: fmt.Sprintf("How the domain gets into the account on create: %s (must already be in the account), %s (AddDomain) or %s (TransferDomain using transfer_auth_code, then waits for the transfer to finish within the create timeout), the latter two need allow_domain_create_delete (default: %s if allow_domain_create_delete is set, %s otherwise)", CREATE_MODE_ADOPT, CREATE_MODE_REGISTER, CREATE_MODE_TRANSFER, CREATE_MODE_REGISTER, CREATE_MODE_ADOPT),
+		},
+		"transfer_auth_code": schema.StringAttribute{
+			Optional:    true,
+			Sensitive:   true,
+			Description: fmt.Sprintf("Auth code from the current registrar, required for create_mode %s", CREATE_MODE_TRANSFER),
+		},
+		"registration_period": schema.Int64Attribute{
+			Optional: true,
+			Validators: []validator.Int64{
+				int64validator.Between(1, MAX_REGISTRATION_PERIOD),
+			},
+			Description: fmt.Sprintf("Number of years to register the domain for with create_mode %s (between 1 and %d, default: registry minimum), changing it later has no effect", CREATE_MODE_REGISTER, MAX_REGISTRATION_PERIOD),
+		},
+		"accept_premium_price": schema.SingleNestedAttribute{
+			Optional: true,
+			Attributes: map[string]schema.Attribute{
+				"max_price": schema.Float64Attribute{
+					Required:    true,
+					Description: "Highest price to accept for the whole registration, that is the price per year times registration_period (1 if not set)",
+				},
+				"currency": schema.StringAttribute{
+					Required:    true,
+					Description: "Currency of max_price (example: USD), the price must be in the same currency",
+				},
+			},
+			Description: fmt.Sprintf("Acknowledges the price of a premium domain for create_mode %s, registering premium domains fails without it or if their price is higher", CREATE_MODE_REGISTER),
+		},
+		"expiry_warning_days": schema.Int64Attribute{
+			Optional: true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+			Description: "Overrides expiry_warning_days of the provider for this domain (0 disables the warning)",
+		},
+		"expiry_error_days": schema.Int64Attribute{
+			Optional: true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+			Description: "Overrides expiry_error_days of the provider for this domain (0 disables the error)",
+		},
+		"on_destroy": schema.StringAttribute{
+			Optional: true,
+			Validators: []validator.String{
+				stringvalidator.OneOf(ON_DESTROY_FORGET, ON_DESTROY_DELETE, ON_DESTROY_AUTOEXPIRE, ON_DESTROY_PUSH),
+			},
+			Description: fmt.Sprintf("What happens to the domain when the resource is destroyed: %s (only remove it from the state), %s (DeleteDomain), %s (set the renewal mode so the domain lapses at the end of its term) or %s (hand it back to the registry/transit), %s and %s need allow_domain_create_delete (default: %s if allow_domain_create_delete is set, %s otherwise)", ON_DESTROY_FORGET, ON_DESTROY_DELETE, ON_DESTROY_AUTOEXPIRE, ON_DESTROY_PUSH, ON_DESTROY_DELETE, ON_DESTROY_PUSH, ON_DESTROY_DELETE, ON_DESTROY_FORGET),
+		},
+	}
+}
+
 type resourceDomain struct {
 	p *localProvider
 }
@@ -17,9 +123,17 @@ func newResourceDomain() resource.Resource {
 	return &resourceDomain{}
 }
 
-func (r *resourceDomain) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *resourceDomain) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Attributes:  makeDomainResourceSchema(),
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+		Attributes:  utils.MergeResourceSchemas(makeDomainResourceSchema(), makeDomainLifecycleSchema()),
 		Description: "Domain object, can be used to configure most attributes of domains",
 	}
 }
@@ -41,21 +155,75 @@ func (r *resourceDomain) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	data := &Domain{}
+	data := &resourceDomainData{}
 	diags := req.Plan.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if r.p.allowDomainCreateDelete {
-		_ = makeDomainCommand(ctx, r.p.client, utils.CommandCreate, data, &Domain{}, &resp.Diagnostics)
+	createTimeout, diags := data.Timeouts.Create(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	data.CreateMode = types.StringValue(r.resolveCreateMode(data.CreateMode))
+	r.checkCreateMode(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch data.CreateMode.ValueString() {
+	case CREATE_MODE_REGISTER:
+		params := r.makeRegistrationParams(ctx, data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		_ = makeDomainCommandWithParams(ctx, r.p.client, utils.CommandCreate, &data.Domain, &Domain{}, params, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// The domain is registered (and paid for) now, so running out of the create timeout must not keep it out of the state
+		var cancelRest context.CancelFunc
+		ctx, cancelRest = context.WithTimeout(context.WithoutCancel(ctx), DEFAULT_READ_TIMEOUT)
+		defer cancelRest()
+		makeDomainRenewalModeUpdate(ctx, r.p.client, &data.Domain, &Domain{}, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	case CREATE_MODE_TRANSFER:
+		// A transfer still pending from an earlier apply which ran into its timeout is waited for again
+		// A failed one is requested again, as applying after fixing the cause is how it gets retried
+		status, _ := queryDomainTransfer(ctx, r.p.client, &data.Domain, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if status == "" || isFailedTransferStatus(status) {
+			_ = makeDomainTransferCommand(ctx, r.p.client, &data.Domain, data.TransferAuthCode, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		waitForDomainTransfer(ctx, r.p.client, &data.Domain, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		r.adoptDomain(ctx, data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	default:
+		r.adoptDomain(ctx, data, &resp.Diagnostics)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
 
-	data = kindDomainRead(ctx, data, r.p.client, &resp.Diagnostics)
+	data.Domain = *kindDomainRead(ctx, &data.Domain, r.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -72,17 +240,29 @@ func (r *resourceDomain) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	data := &Domain{}
+	data := &resourceDomainData{}
 	diags := req.State.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindDomainRead(ctx, data, r.p.client, &resp.Diagnostics)
+	readTimeout, diags := data.Timeouts.Read(ctx, DEFAULT_READ_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	readDiags := diag.Diagnostics{}
+	data.Domain = *kindDomainRead(ctx, &data.Domain, r.p.client, &readDiags)
+	if utils.RemoveIfNotFound(ctx, readDiags, &resp.State, &resp.Diagnostics) {
+		return
+	}
+	// Only warns, failing is left to plans so refreshing never blocks fixing the renewal mode
+	checkDomainExpiry(&data.Domain, r.p.expiryThresholds.override(data.ExpiryWarningDays, data.ExpiryErrorDays), time.Now(), true, false, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -96,26 +276,39 @@ func (r *resourceDomain) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	data := &Domain{}
+	data := &resourceDomainData{}
 	diags := req.Plan.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	dataOld := &Domain{}
+	updateTimeout, diags := data.Timeouts.Update(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	dataOld := &resourceDomainData{}
 	diags = req.State.Get(ctx, dataOld)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_ = makeDomainCommand(ctx, r.p.client, utils.CommandUpdate, data, dataOld, &resp.Diagnostics)
+	_ = makeDomainCommand(ctx, r.p.client, utils.CommandUpdate, &data.Domain, &dataOld.Domain, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	makeDomainRenewalModeUpdate(ctx, r.p.client, &data.Domain, &dataOld.Domain, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindDomainRead(ctx, data, r.p.client, &resp.Diagnostics)
+	data.Domain = *kindDomainRead(ctx, &data.Domain, r.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -132,26 +325,279 @@ func (r *resourceDomain) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	dataOld := &Domain{}
+	dataOld := &resourceDomainData{}
 	diags := req.State.Get(ctx, dataOld)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if r.p.allowDomainCreateDelete {
+	deleteTimeout, diags := dataOld.Timeouts.Delete(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	onDestroy := r.resolveOnDestroy(dataOld.OnDestroy)
+	r.checkOnDestroy(onDestroy, dataOld.Domain.Domain.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch onDestroy {
+	case ON_DESTROY_DELETE:
 		_ = makeDomainCommand(ctx, r.p.client, utils.CommandDelete, &Domain{
-			Domain: dataOld.Domain,
-		}, dataOld, &resp.Diagnostics)
+			Domain: dataOld.Domain.Domain,
+		}, &dataOld.Domain, &resp.Diagnostics)
+	case ON_DESTROY_AUTOEXPIRE:
+		_ = makeDomainRenewalModeCommand(ctx, r.p.client, &dataOld.Domain, RENEWAL_MODE_AUTOEXPIRE, &resp.Diagnostics)
+	case ON_DESTROY_PUSH:
+		_ = makeDomainPushCommand(ctx, r.p.client, &dataOld.Domain, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// Defaults to the behaviour from before create_mode existed
+func (r *resourceDomain) resolveCreateMode(createMode types.String) string {
+	if !createMode.IsNull() && !createMode.IsUnknown() {
+		return createMode.ValueString()
+	}
+	if r.p.allowDomainCreateDelete {
+		return CREATE_MODE_REGISTER
+	}
+	return CREATE_MODE_ADOPT
+}
+
+// Checks whether the create mode may be used for the domain, data.CreateMode must be resolved already
+func (r *resourceDomain) checkCreateMode(data *resourceDomainData, diags *diag.Diagnostics) {
+	createMode := data.CreateMode.ValueString()
+	if createMode == CREATE_MODE_ADOPT {
+		return
+	}
+
+	if !r.p.allowDomainCreateDelete {
+		diags.AddError("Domain creation not allowed", fmt.Sprintf("create_mode %s needs allow_domain_create_delete to be enabled on the provider", createMode))
+		return
+	}
+
+	if createMode == CREATE_MODE_TRANSFER && data.TransferAuthCode.IsNull() {
+		diags.AddError("Missing auth code", fmt.Sprintf("transfer_auth_code is required for create_mode %s", CREATE_MODE_TRANSFER))
+		return
+	}
+
+	if !data.Domain.Domain.IsUnknown() {
+		r.p.domainProtection.check(domainOperationCreate, data.Domain.Domain.ValueString(), diags)
+	}
+}
+
+// Defaults to the behaviour from before on_destroy existed
+func (r *resourceDomain) resolveOnDestroy(onDestroy types.String) string {
+	if !onDestroy.IsNull() && !onDestroy.IsUnknown() {
+		return onDestroy.ValueString()
+	}
+	if r.p.allowDomainCreateDelete {
+		return ON_DESTROY_DELETE
+	}
+	return ON_DESTROY_FORGET
+}
+
+// Checks whether the domain may be destroyed with the given on_destroy mode
+func (r *resourceDomain) checkOnDestroy(onDestroy string, domain string, diags *diag.Diagnostics) {
+	if onDestroy == ON_DESTROY_FORGET {
+		return
+	}
+
+	if (onDestroy == ON_DESTROY_DELETE || onDestroy == ON_DESTROY_PUSH) && !r.p.allowDomainCreateDelete {
+		diags.AddError("Domain deletion not allowed", fmt.Sprintf("on_destroy %s needs allow_domain_create_delete to be enabled on the provider", onDestroy))
+		return
+	}
+
+	// All modes except forget make the domain leave the account (sooner or later)
+	if domain != "" {
+		r.p.domainProtection.check(domainOperationDelete, domain, diags)
+	}
+}
+
+// Warns about on_destroy modes the provider settings do not allow, without blocking plans which do not destroy the domain
+func (r *resourceDomain) warnOnDestroy(onDestroy string, diags *diag.Diagnostics) {
+	checkDiags := diag.Diagnostics{}
+	r.checkOnDestroy(onDestroy, "", &checkDiags)
+	for _, d := range checkDiags.Errors() {
+		diags.AddAttributeWarning(path.Root("on_destroy"), d.Summary(), d.Detail()+", destroying or replacing the domain fails until then")
+	}
+}
+
+// Builds the AddDomain parameters for registration_period and, after checking the price, premium domains
+func (r *resourceDomain) makeRegistrationParams(ctx context.Context, data *resourceDomainData, diags *diag.Diagnostics) map[string]interface{} {
+	params := make(map[string]interface{})
+	if !data.RegistrationPeriod.IsNull() && !data.RegistrationPeriod.IsUnknown() {
+		params["PERIOD"] = fmt.Sprintf("%d", data.RegistrationPeriod.ValueInt64())
+	}
+
+	domain := data.Domain.Domain.ValueString()
+	checks := makeDomainCheckCommand(ctx, r.p.client, []string{domain}, diags)
+	if diags.HasError() {
+		return nil
+	}
+	check := checks[0]
+	if !check.Premium {
+		return params
+	}
+
+	if data.AcceptPremiumPrice.IsNull() || data.AcceptPremiumPrice.IsUnknown() {
+		diags.AddError("Premium domain", fmt.Sprintf("Domain %s is a premium domain (class %s) costing %s %s per year, set accept_premium_price to register it", domain, check.Class, check.Price, check.Currency))
+		return nil
+	}
+	accepted := &domainPremiumPriceData{}
+	diags.Append(data.AcceptPremiumPrice.As(ctx, accepted, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil
+	}
+
+	price, err := strconv.ParseFloat(check.Price, 64)
+	if err != nil {
+		diags.AddError("Premium domain", fmt.Sprintf("Domain %s is a premium domain, but the API did not return a usable price (%q), so it was not registered", domain, check.Price))
+		return nil
+	}
+	if !strings.EqualFold(check.Currency, accepted.Currency.ValueString()) {
+		diags.AddError("Premium price not accepted", fmt.Sprintf("Domain %s costs %s %s per year, but accept_premium_price is in %s", domain, check.Price, check.Currency, accepted.Currency.ValueString()))
+		return nil
+	}
+
+	// The price is per year, max_price and the acknowledged fee cover the whole registration period
+	years := int64(1)
+	if !data.RegistrationPeriod.IsNull() && !data.RegistrationPeriod.IsUnknown() {
+		years = data.RegistrationPeriod.ValueInt64()
+	}
+	total := math.Round(price*float64(years)*100) / 100
+	if total > accepted.MaxPrice.ValueFloat64() {
+		diags.AddError("Premium price not accepted", fmt.Sprintf("Domain %s costs %s %s per year, %.2f %s for %d years, which is more than the accepted max_price of %g %s", domain, check.Price, check.Currency, total, check.Currency, years, accepted.MaxPrice.ValueFloat64(), accepted.Currency.ValueString()))
+		return nil
+	}
+
+	params["CLASS"] = check.Class
+	params["X-FEE-AMOUNT"] = strconv.FormatFloat(total, 'f', 2, 64)
+	return params
+}
 
+// Takes over a domain which is already in the account and brings it in line with the plan
+func (r *resourceDomain) adoptDomain(ctx context.Context, data *resourceDomainData, diags *diag.Diagnostics) {
+	readDiags := diag.Diagnostics{}
+	existing := kindDomainRead(ctx, &data.Domain, r.p.client, &readDiags)
+	if utils.IsNotFound(readDiags) && data.CreateMode.ValueString() == CREATE_MODE_ADOPT {
+		diags.AddError("Domain not in account", fmt.Sprintf("Domain %s is not in the account, create_mode %s only takes over existing domains", data.Domain.Domain.ValueString(), CREATE_MODE_ADOPT))
+		return
+	}
+	diags.Append(readDiags...)
+	if diags.HasError() {
+		return
+	}
+
+	_ = makeDomainCommand(ctx, r.p.client, utils.CommandUpdate, &data.Domain, existing, diags)
+	if diags.HasError() {
+		return
+	}
+	makeDomainRenewalModeUpdate(ctx, r.p.client, &data.Domain, existing, diags)
+}
+
+// Shows the effective create_mode in the plan and checks create_mode, on_destroy and domain_protection
+// while planning already, so blocked registrations and deletions never make it into a plan
+// Also warns about (or fails on) domains about to expire, see expiry_warning_days
+func (r *resourceDomain) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.p == nil || !r.p.configured {
+		return
+	}
+
+	if req.State.Raw.IsNull() && !req.Plan.Raw.IsNull() {
+		data := &resourceDomainData{}
+		resp.Diagnostics.Append(req.Plan.Get(ctx, data)...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
+
+		data.CreateMode = types.StringValue(r.resolveCreateMode(data.CreateMode))
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_mode"), data.CreateMode)...)
+		r.checkCreateMode(data, &resp.Diagnostics)
+		if !data.OnDestroy.IsNull() && !data.OnDestroy.IsUnknown() {
+			r.warnOnDestroy(data.OnDestroy.ValueString(), &resp.Diagnostics)
+		}
+		return
 	}
 
-	resp.State.RemoveResource(ctx)
+	if !req.Plan.Raw.IsNull() {
+		var onDestroy types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("on_destroy"), &onDestroy)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Point out modes which could never be used early, not only once the domain is destroyed
+		if !onDestroy.IsNull() && !onDestroy.IsUnknown() {
+			r.warnOnDestroy(onDestroy.ValueString(), &resp.Diagnostics)
+		}
+
+		// State from before create_mode existed has no value for it, which is kept instead of ending up unknown
+		var createMode types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("create_mode"), &createMode)...)
+		if createMode.IsUnknown() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("create_mode"), &createMode)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("create_mode"), createMode)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var oldDomain, newDomain types.String
+	var oldOnDestroy types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("domain"), &oldDomain)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("on_destroy"), &oldOnDestroy)...)
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &newDomain)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Updates in place never destroy, replacements (changing the domain name) do
+	// Terraform plans the create part of a replacement separately, which is checked above
+	// Destroying always uses on_destroy from the state, as with any other attribute
+	isReplace := !req.Plan.Raw.IsNull() && !oldDomain.Equal(newDomain)
+	if req.Plan.Raw.IsNull() || isReplace {
+		if !oldDomain.IsUnknown() && !oldDomain.IsNull() {
+			r.checkOnDestroy(r.resolveOnDestroy(oldOnDestroy), oldDomain.ValueString(), &resp.Diagnostics)
+		}
+	}
+
+	// Only checked while planning, so an error never blocks the apply changing renewal_mode to fix it
+	// The dates are unknown in the plan (a renewal changes them), so they come from the state, as does the
+	// renewal mode unless the config changes it
+	if !req.Plan.Raw.IsNull() && !isReplace {
+		data := &resourceDomainData{}
+		resp.Diagnostics.Append(req.Plan.Get(ctx, data)...)
+		current := &resourceDomainData{}
+		resp.Diagnostics.Append(req.State.Get(ctx, current)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Read already warned about the renewal mode in the state, so only a changed one is warned about again
+		renewalModeChanged := false
+		if !data.Domain.RenewalMode.IsUnknown() {
+			renewalModeChanged = !data.Domain.RenewalMode.Equal(current.Domain.RenewalMode)
+			current.Domain.RenewalMode = data.Domain.RenewalMode
+		}
+		checkDomainExpiry(&current.Domain, r.p.expiryThresholds.override(data.ExpiryWarningDays, data.ExpiryErrorDays), time.Now(), renewalModeChanged, true, &resp.Diagnostics)
+	}
 }
 
 func (r *resourceDomain) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("domain"), req, resp)
+	// Imported domains were already in the account
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("create_mode"), CREATE_MODE_ADOPT)...)
 }
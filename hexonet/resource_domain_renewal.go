@@ -0,0 +1,248 @@
+package hexonet
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const MAX_RENEWAL_PERIOD = 10
+
+type resourceDomainRenewalData struct {
+	Domain                     types.String   `tfsdk:"domain"`
+	Period                     types.Int64    `tfsdk:"period"`
+	ExpectedExpirationDate     types.String   `tfsdk:"expected_expiration_date"`
+	RegistrationExpirationDate types.String   `tfsdk:"registration_expiration_date"`
+	PaidUntilDate              types.String   `tfsdk:"paid_until_date"`
+	Timeouts                   timeouts.Value `tfsdk:"timeouts"`
+}
+
+type resourceDomainRenewal struct {
+	p *localProvider
+}
+
+func newResourceDomainRenewal() resource.Resource {
+	return &resourceDomainRenewal{}
+}
+
+func (r *resourceDomainRenewal) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "Domain name to renew (example: example.com)",
+			},
+			"period": schema.Int64Attribute{
+				Required: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, MAX_RENEWAL_PERIOD),
+				},
+				Description: fmt.Sprintf("Number of years to renew the domain for (between 1 and %d)", MAX_RENEWAL_PERIOD),
+			},
+			"expected_expiration_date": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`), "must start with a date (YYYY-MM-DD)"),
+				},
+				Description: "Registration expiration date (YYYY-MM-DD or RFC 3339) the domain must have for the renewal to be sent, use a literal value (not registration_expiration_date of hexonet_domain), so applying again never renews twice",
+			},
+			"registration_expiration_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Date the registration expires at the registry, after the renewal (RFC 3339)",
+			},
+			"paid_until_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Date the domain is paid until in the account, after the renewal (RFC 3339)",
+			},
+		},
+		Description: "Renewal of a domain by a number of years, sent once when created, destroying it only removes it from the state",
+	}
+}
+
+func (r *resourceDomainRenewal) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.p = req.ProviderData.(*localProvider)
+}
+
+func (r *resourceDomainRenewal) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_renewal"
+}
+
+// Accepts plain dates as well as full timestamps, only the (UTC) date is compared
+func parseExpirationDate(str string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, str)
+	if err == nil {
+		return t.UTC().Truncate(24 * time.Hour), nil
+	}
+	return time.Parse("2006-01-02", str)
+}
+
+func isSameDate(a time.Time, b time.Time) bool {
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+func (r *resourceDomainRenewal) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.p.configured {
+		utils.MakeNotConfiguredError(&resp.Diagnostics)
+		return
+	}
+
+	data := &resourceDomainRenewalData{}
+	diags := req.Plan.Get(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	expected, err := parseExpirationDate(data.ExpectedExpirationDate.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid expected_expiration_date", err.Error())
+		return
+	}
+
+	domain := kindDomainRead(ctx, &Domain{Domain: data.Domain}, r.p.client, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	current, err := parseExpirationDate(domain.RegistrationExpirationDate.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Can not read expiration date", fmt.Sprintf("Domain %s has no usable expiration date: %s", data.Domain.ValueString(), err.Error()))
+		return
+	}
+
+	period := int(data.Period.ValueInt64())
+	switch {
+	case isSameDate(current, expected):
+		_ = makeDomainRenewCommand(ctx, r.p.client, domain, period, expected, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		domain = kindDomainRead(ctx, domain, r.p.client, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	case isSameDate(current, expected.AddDate(period, 0, 0)):
+		// An earlier apply already renewed the domain but did not get to store the result
+	default:
+		resp.Diagnostics.AddError(
+			"Unexpected expiration date",
+			fmt.Sprintf("Domain %s expires on %s, not on the expected_expiration_date %s, so it was not renewed (it might have been renewed already)", data.Domain.ValueString(), domain.RegistrationExpirationDate.ValueString(), data.ExpectedExpirationDate.ValueString()),
+		)
+		return
+	}
+
+	data.RegistrationExpirationDate = domain.RegistrationExpirationDate
+	data.PaidUntilDate = domain.PaidUntilDate
+	diags = resp.State.Set(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *resourceDomainRenewal) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !r.p.configured {
+		utils.MakeNotConfiguredError(&resp.Diagnostics)
+		return
+	}
+
+	data := &resourceDomainRenewalData{}
+	diags := req.State.Get(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, DEFAULT_READ_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	readDiags := diag.Diagnostics{}
+	domain := kindDomainRead(ctx, &Domain{Domain: data.Domain}, r.p.client, &readDiags)
+	// Once the domain left the account, there is nothing left to renew
+	if utils.RemoveIfNotFound(ctx, readDiags, &resp.State, &resp.Diagnostics) {
+		return
+	}
+
+	data.RegistrationExpirationDate = domain.RegistrationExpirationDate
+	data.PaidUntilDate = domain.PaidUntilDate
+	diags = resp.State.Set(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Every attribute except timeouts requires replacement, so there is nothing to send
+func (r *resourceDomainRenewal) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	data := &resourceDomainRenewalData{}
+	diags := req.Plan.Get(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dataOld := &resourceDomainRenewalData{}
+	diags = req.State.Get(ctx, dataOld)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.RegistrationExpirationDate = dataOld.RegistrationExpirationDate
+	data.PaidUntilDate = dataOld.PaidUntilDate
+
+	diags = resp.State.Set(ctx, data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Renewals can not be undone, so destroying only forgets about it
+func (r *resourceDomainRenewal) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,122 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testRenewalDomainConfig(nameServer string, renewal bool) string {
+	config := providerConfig(false, "")
+	dependsOn := ""
+	if renewal {
+		config += fmt.Sprintf(`
+resource "hexonet_domain_renewal" "test" {
+  domain                   = %q
+  period                   = 1
+  expected_expiration_date = "2030-01-01"
+}
+`, testDomainName)
+		dependsOn = "depends_on = [hexonet_domain_renewal.test]"
+	}
+	return config + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain       = %q
+  name_servers = [%q]
+  %s
+}
+`, testDomainName, nameServer, dependsOn)
+}
+
+func TestAccDomainRenewal_updateDomainInSameApply(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetDomain(testDomainName, fakeapi.Object{
+		"OWNERCONTACT":               {"P-EXISTING"},
+		"REGISTRATIONEXPIRATIONDATE": {"2030-01-01 00:00:00"},
+		"PAIDUNTILDATE":              {"2030-01-01 00:00:00"},
+		"RENEWALDATE":                {"2030-01-01 00:00:00"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testRenewalDomainConfig("ns1.example.net", false),
+				Check:  resource.TestCheckResourceAttr("hexonet_domain.test", "registration_expiration_date", "2030-01-01T00:00:00Z"),
+			},
+			{
+				// The renewal changes the dates of the domain updated afterwards, which must not be planned as unchanged
+				Config: testRenewalDomainConfig("ns2.example.net", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain_renewal.test", "registration_expiration_date", "2031-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "registration_expiration_date", "2031-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "paid_until_date", "2031-01-01T00:00:00Z"),
+					testCheckCommandCount(fake, "RenewDomain", 1),
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns2.example.net"),
+				),
+			},
+		},
+	})
+}
+
+func testRenewalConfig(expectedExpirationDate string) string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_domain_renewal" "test" {
+  domain                   = %q
+  period                   = 1
+  expected_expiration_date = %q
+}
+`, testDomainName, expectedExpirationDate)
+}
+
+func newFakeServerWithExpiringDomain(t *testing.T, expiration string) *fakeapi.Server {
+	fake := newFakeServer(t)
+	fake.SetDomain(testDomainName, fakeapi.Object{
+		"OWNERCONTACT":               {"P-EXISTING"},
+		"REGISTRATIONEXPIRATIONDATE": {expiration},
+		"PAIDUNTILDATE":              {expiration},
+		"RENEWALDATE":                {expiration},
+	})
+	return fake
+}
+
+func TestAccDomainRenewal_unexpectedExpirationDate(t *testing.T) {
+	fake := newFakeServerWithExpiringDomain(t, "2029-06-01 00:00:00")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckCommandCount(fake, "RenewDomain", 0),
+		Steps: []resource.TestStep{
+			{
+				Config:      testRenewalConfig("2030-01-01"),
+				ExpectError: regexp.MustCompile(`expires on 2029-06-01T00:00:00Z, not on the\s+expected_expiration_date 2030-01-01, so it was not renewed`),
+			},
+		},
+	})
+}
+
+func TestAccDomainRenewal_alreadyRenewed(t *testing.T) {
+	// Renewed by an earlier apply which did not get to store the result
+	fake := newFakeServerWithExpiringDomain(t, "2031-01-01 00:00:00")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testRenewalConfig("2030-01-01"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain_renewal.test", "registration_expiration_date", "2031-01-01T00:00:00Z"),
+					testCheckCommandCount(fake, "RenewDomain", 0),
+				),
+			},
+			{
+				// Applying again does not renew once more either
+				Config: testRenewalConfig("2030-01-01"),
+				Check:  testCheckCommandCount(fake, "RenewDomain", 0),
+			},
+		},
+	})
+}
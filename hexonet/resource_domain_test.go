@@ -0,0 +1,306 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+const testDomainName = "example.com"
+
+func testDomainConfig(allowDomainCreateDelete bool, domain string) string {
+	return providerConfig(allowDomainCreateDelete, "") + fmt.Sprintf(`
+resource "hexonet_contact" "owner" {
+  %s
+}
+
+resource "hexonet_domain" "test" {
+  domain         = %q
+  owner_contacts = [hexonet_contact.owner.id]
+  admin_contacts = [hexonet_contact.owner.id]
+  %s
+}
+
+data "hexonet_domain" "test" {
+  domain = hexonet_domain.test.domain
+}
+`, testContactRequired, testDomainName, domain)
+}
+
+// Without a contact resource, as adopted domains keep their contacts in use when they are forgotten
+func testAdoptDomainConfig(domain string) string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain = %q
+  %s
+}
+`, testDomainName, domain)
+}
+
+func testCheckDomainDestroyed(fake *fakeapi.Server) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		if fake.Domain(testDomainName) != nil {
+			return fmt.Errorf("domain %s still exists", testDomainName)
+		}
+		return nil
+	}
+}
+
+func testCheckFakeDomainColumn(fake *fakeapi.Server, column string, expected ...string) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		obj := fake.Domain(testDomainName)
+		if obj == nil {
+			return fmt.Errorf("domain %s does not exist", testDomainName)
+		}
+		actual := append([]string{}, obj[column]...)
+		sort.Strings(actual)
+		sort.Strings(expected)
+		if strings.Join(actual, ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("expected %s %v, got %v", column, expected, actual)
+		}
+		return nil
+	}
+}
+
+func TestAccDomain_basic(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testDomainConfig(true, `
+  name_servers = ["ns1.example.net", "ns2.example.net"]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "domain", testDomainName),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "create_mode", "register"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "name_servers.#", "2"),
+					resource.TestCheckTypeSetElemAttr("hexonet_domain.test", "name_servers.*", "ns1.example.net"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "dnssec_max_sig_lifespan", "0"),
+					resource.TestCheckResourceAttrSet("hexonet_domain.test", "auth_code"),
+					resource.TestCheckResourceAttrPair("data.hexonet_domain.test", "owner_contacts.0", "hexonet_contact.owner", "id"),
+					resource.TestCheckResourceAttrPair("data.hexonet_domain.test", "auth_code", "hexonet_domain.test", "auth_code"),
+					resource.TestCheckResourceAttr("data.hexonet_domain.test", "name_servers.#", "2"),
+					// Lists and values left to the API are not sent at all
+					testCheckLastCommand(fake, "AddDomain", map[string]string{
+						"NAMESERVER0":       "ns1.example.net",
+						"STATUS0":           "",
+						"TECHCONTACT0":      "",
+						"SECDNS-MAXSIGLIFE": "",
+					}),
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns1.example.net", "ns2.example.net"),
+				),
+			},
+			{
+				ResourceName:                         "hexonet_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        testDomainName,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain",
+				// Imported domains are always adopted
+				ImportStateVerifyIgnore: []string{"create_mode"},
+			},
+			{
+				Config: testDomainConfig(true, `
+  name_servers            = ["ns1.example.net", "ns3.example.net"]
+  dnssec_max_sig_lifespan = 86400
+  extra_attributes = {
+    "FOO" = "bar"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "create_mode", "register"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "name_servers.#", "2"),
+					resource.TestCheckTypeSetElemAttr("hexonet_domain.test", "name_servers.*", "ns3.example.net"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "dnssec_max_sig_lifespan", "86400"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "extra_attributes.FOO", "bar"),
+					resource.TestCheckResourceAttr("data.hexonet_domain.test", "dnssec_max_sig_lifespan", "86400"),
+					testCheckLastCommand(fake, "ModifyDomain", map[string]string{
+						"DELNAMESERVER0":    "ns2.example.net",
+						"SECDNS-MAXSIGLIFE": "86400",
+						"X-FOO":             "bar",
+					}),
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns1.example.net", "ns3.example.net"),
+					testCheckCommandCount(fake, "AddDomain", 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomain_adopt(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetDomain(testDomainName, fakeapi.Object{
+		"NAMESERVER":   {"ns1.old.example.net", "ns2.old.example.net"},
+		"STATUS":       {"clientTransferProhibited"},
+		"OWNERCONTACT": {"P-EXISTING"},
+		"AUTH":         {"existing-auth"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		// Without allow_domain_create_delete, destroying only removes the domain from the state
+		CheckDestroy: func(_ *terraform.State) error {
+			if fake.Domain(testDomainName) == nil {
+				return fmt.Errorf("domain %s was deleted", testDomainName)
+			}
+			return testCheckCommandCount(fake, "DeleteDomain", 0)(nil)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAdoptDomainConfig(`
+  name_servers = ["ns1.example.net", "ns2.example.net"]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "create_mode", "adopt"),
+					resource.TestCheckResourceAttr("hexonet_domain.test", "auth_code", "existing-auth"),
+					// Attributes which are not configured are kept as they are in the account
+					resource.TestCheckResourceAttr("hexonet_domain.test", "status.#", "1"),
+					resource.TestCheckTypeSetElemAttr("hexonet_domain.test", "status.*", "clientTransferProhibited"),
+					resource.TestCheckTypeSetElemAttr("hexonet_domain.test", "owner_contacts.*", "P-EXISTING"),
+					// The adopted domain is brought in line with the config using ModifyDomain
+					testCheckCommandCount(fake, "AddDomain", 0),
+					testCheckLastCommand(fake, "ModifyDomain", map[string]string{
+						"NAMESERVER0":    "ns1.example.net",
+						"NAMESERVER1":    "ns2.example.net",
+						"DELNAMESERVER0": "ns1.old.example.net",
+						"DELNAMESERVER1": "ns2.old.example.net",
+						"STATUS0":        "",
+						"OWNERCONTACT0":  "",
+					}),
+					testCheckFakeDomainColumn(fake, "NAMESERVER", "ns1.example.net", "ns2.example.net"),
+					testCheckFakeDomainColumn(fake, "STATUS", "clientTransferProhibited"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomain_adoptMissing(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAdoptDomainConfig(""),
+				ExpectError: regexp.MustCompile("is not in the account"),
+			},
+		},
+	})
+}
+
+func testPremiumDomainConfig(period int, maxPrice string, currency string) string {
+	attrs := fmt.Sprintf("registration_period = %d\n", period)
+	if maxPrice != "" {
+		attrs += fmt.Sprintf(`
+  accept_premium_price = {
+    max_price = %s
+    currency  = %q
+  }
+`, maxPrice, currency)
+	}
+	return testDomainConfig(true, attrs)
+}
+
+func TestAccDomain_premium(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetPremiumDomain(testDomainName, "PREMIUM_TEST", 100, "USD")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config:      testPremiumDomainConfig(2, "", ""),
+				ExpectError: regexp.MustCompile(`set accept_premium_price to\s+register it`),
+			},
+			{
+				Config:      testPremiumDomainConfig(2, "500", "EUR"),
+				ExpectError: regexp.MustCompile(`accept_premium_price is in\s+EUR`),
+			},
+			{
+				// 100 per year is accepted for a single year only
+				Config:      testPremiumDomainConfig(2, "150", "USD"),
+				ExpectError: regexp.MustCompile(`200.00 USD for 2 years, which\s+is more than the accepted max_price of 150 USD`),
+			},
+			{
+				Config: testPremiumDomainConfig(2, "200", "usd"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_domain.test", "registration_period", "2"),
+					// The acknowledged fee covers both years
+					testCheckLastCommand(fake, "AddDomain", map[string]string{
+						"PERIOD":       "2",
+						"CLASS":        "PREMIUM_TEST",
+						"X-FEE-AMOUNT": "200.00",
+					}),
+					testCheckCommandCount(fake, "AddDomain", 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomain_registrationPeriod(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckDomainDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testPremiumDomainConfig(3, "", ""),
+				Check: testCheckLastCommand(fake, "AddDomain", map[string]string{
+					"PERIOD":       "3",
+					"CLASS":        "",
+					"X-FEE-AMOUNT": "",
+				}),
+			},
+		},
+	})
+}
+
+func testProtectedDomainConfig(domainProtection string, domain string) string {
+	return providerConfig(true, fmt.Sprintf("domain_protection {\n    %s\n  }", domainProtection)) + fmt.Sprintf(`
+resource "hexonet_domain" "test" {
+  domain = %q
+  %s
+}
+`, testDomainName, domain)
+}
+
+func TestAccDomain_protection(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.SetForeignDomain(testDomainName, testTransferAuthCode)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		// The rules are checked when planning, so nothing may have been sent
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testCheckCommandCount(fake, "AddDomain", 0),
+			testCheckCommandCount(fake, "TransferDomain", 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				// An empty allow list allows no domain at all
+				Config:      testProtectedDomainConfig(`allow_create = []`, `create_mode = "register"`),
+				ExpectError: regexp.MustCompile(`Domain example.com does not match any allow_create pattern`),
+			},
+			{
+				Config: testProtectedDomainConfig(`deny_create = ["^example\\.(com|net)"]`, fmt.Sprintf(`
+  create_mode        = "transfer"
+  transfer_auth_code = %q
+`, testTransferAuthCode)),
+				ExpectError: regexp.MustCompile(`Domain example.com matches deny_create pattern`),
+			},
+		},
+	})
+}
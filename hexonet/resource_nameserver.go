@@ -4,11 +4,18 @@ import (
 	"context"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
+type resourceNameServerData struct {
+	NameServer
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
 type resourceNameServer struct {
 	p *localProvider
 }
@@ -17,8 +24,16 @@ func newResourceNameServer() resource.Resource {
 	return &resourceNameServer{}
 }
 
-func (r *resourceNameServer) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *resourceNameServer) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 		Attributes:  makeNameServerResourceSchema(),
 		Description: "Nameserver object, used to register so-called \"glue\" records when a domain's nameservers use hosts on the same domain (example: example.com using ns1.example.com)",
 	}
@@ -41,19 +56,28 @@ func (r resourceNameServer) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	data := &NameServer{}
+	data := &resourceNameServerData{}
 	diags := req.Plan.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_ = makeNameServerCommand(ctx, r.p.client, utils.CommandCreate, data, &NameServer{}, &resp.Diagnostics)
+	createTimeout, diags := data.Timeouts.Create(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindNameserverRead(ctx, data, r.p.client, &resp.Diagnostics)
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	_ = makeNameServerCommand(ctx, r.p.client, utils.CommandCreate, &data.NameServer, &NameServer{}, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.NameServer = *kindNameserverRead(ctx, &data.NameServer, r.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -70,17 +94,27 @@ func (r resourceNameServer) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	data := &NameServer{}
+	data := &resourceNameServerData{}
 	diags := req.State.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindNameserverRead(ctx, data, r.p.client, &resp.Diagnostics)
+	readTimeout, diags := data.Timeouts.Read(ctx, DEFAULT_READ_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	readDiags := diag.Diagnostics{}
+	data.NameServer = *kindNameserverRead(ctx, &data.NameServer, r.p.client, &readDiags)
+	if utils.RemoveIfNotFound(ctx, readDiags, &resp.State, &resp.Diagnostics) {
+		return
+	}
 	diags = resp.State.Set(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -94,26 +128,35 @@ func (r resourceNameServer) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	data := &NameServer{}
+	data := &resourceNameServerData{}
 	diags := req.Plan.Get(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	dataOld := &NameServer{}
+	updateTimeout, diags := data.Timeouts.Update(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	dataOld := &resourceNameServerData{}
 	diags = req.State.Get(ctx, dataOld)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_ = makeNameServerCommand(ctx, r.p.client, utils.CommandUpdate, data, dataOld, &resp.Diagnostics)
+	_ = makeNameServerCommand(ctx, r.p.client, utils.CommandUpdate, &data.NameServer, &dataOld.NameServer, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data = kindNameserverRead(ctx, data, r.p.client, &resp.Diagnostics)
+	data.NameServer = *kindNameserverRead(ctx, &data.NameServer, r.p.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -130,16 +173,25 @@ func (r resourceNameServer) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	dataOld := &NameServer{}
-	diags := req.State.Get(ctx, &dataOld)
+	dataOld := &resourceNameServerData{}
+	diags := req.State.Get(ctx, dataOld)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	deleteTimeout, diags := dataOld.Timeouts.Delete(ctx, DEFAULT_OPERATION_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	_ = makeNameServerCommand(ctx, r.p.client, utils.CommandDelete, &NameServer{
-		Host: dataOld.Host,
-	}, dataOld, &resp.Diagnostics)
+		Host: dataOld.NameServer.Host,
+	}, &dataOld.NameServer, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -0,0 +1,139 @@
+package hexonet_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Doridian/terraform-provider-hexonet/hexonet/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+const testNameserverHost = "ns1.example.com"
+
+func testNameserverConfig(ipAddresses ...string) string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_nameserver" "test" {
+  host         = %q
+  ip_addresses = ["%s"]
+}
+
+data "hexonet_nameserver" "test" {
+  host = hexonet_nameserver.test.host
+}
+`, testNameserverHost, strings.Join(ipAddresses, `", "`))
+}
+
+// Without the data source, which could not read the nameserver once it is removed
+func testNameserverOnlyConfig() string {
+	return providerConfig(false, "") + fmt.Sprintf(`
+resource "hexonet_nameserver" "test" {
+  host         = %q
+  ip_addresses = ["192.0.2.1"]
+}
+`, testNameserverHost)
+}
+
+func testCheckNameserverDestroyed(fake *fakeapi.Server) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		if fake.Nameserver(testNameserverHost) != nil {
+			return fmt.Errorf("nameserver %s still exists", testNameserverHost)
+		}
+		return nil
+	}
+}
+
+func testCheckFakeNameserverIPs(fake *fakeapi.Server, expected ...string) func(*terraform.State) error {
+	return func(_ *terraform.State) error {
+		obj := fake.Nameserver(testNameserverHost)
+		if obj == nil {
+			return fmt.Errorf("nameserver %s does not exist", testNameserverHost)
+		}
+		if strings.Join(obj["IPADDRESS"], ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("expected IP addresses %v, got %v", expected, obj["IPADDRESS"])
+		}
+		return nil
+	}
+}
+
+func TestAccNameserver_basic(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckNameserverDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testNameserverConfig("192.0.2.1", "2001:db8::1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "host", testNameserverHost),
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "ip_addresses.#", "2"),
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "ip_addresses.0", "192.0.2.1"),
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "ip_addresses.1", "2001:db8::1"),
+					resource.TestCheckResourceAttr("data.hexonet_nameserver.test", "ip_addresses.#", "2"),
+					resource.TestCheckResourceAttr("data.hexonet_nameserver.test", "ip_addresses.1", "2001:db8::1"),
+					testCheckFakeNameserverIPs(fake, "192.0.2.1", "2001:db8::1"),
+				),
+			},
+			{
+				ResourceName:                         "hexonet_nameserver.test",
+				ImportState:                          true,
+				ImportStateId:                        testNameserverHost,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "host",
+			},
+			{
+				// Replaces one address and adds another one
+				Config: testNameserverConfig("192.0.2.2", "2001:db8::1", "192.0.2.3"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "ip_addresses.#", "3"),
+					resource.TestCheckResourceAttr("data.hexonet_nameserver.test", "ip_addresses.#", "3"),
+					testCheckLastCommand(fake, "ModifyNameserver", map[string]string{
+						"IPADDRESS0":    "192.0.2.2",
+						"IPADDRESS1":    "2001:db8::1",
+						"IPADDRESS2":    "192.0.2.3",
+						"DELIPADDRESS0": "192.0.2.1",
+					}),
+					testCheckFakeNameserverIPs(fake, "192.0.2.2", "2001:db8::1", "192.0.2.3"),
+				),
+			},
+			{
+				// Shrinking the list blanks the trailing entries
+				Config: testNameserverConfig("2001:db8::1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "ip_addresses.#", "1"),
+					resource.TestCheckResourceAttr("hexonet_nameserver.test", "ip_addresses.0", "2001:db8::1"),
+					testCheckFakeNameserverIPs(fake, "2001:db8::1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNameserver_removedOutsideTerraform(t *testing.T) {
+	fake := newFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(fake),
+		CheckDestroy:             testCheckNameserverDestroyed(fake),
+		Steps: []resource.TestStep{
+			{
+				Config: testNameserverOnlyConfig(),
+				Check: func(_ *terraform.State) error {
+					fake.DeleteNameserver(testNameserverHost)
+					return nil
+				},
+				// Reading the missing nameserver removes it from the state, so it is planned to be created again
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testNameserverOnlyConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckFakeNameserverIPs(fake, "192.0.2.1"),
+					testCheckCommandCount(fake, "AddNameserver", 2),
+				),
+			},
+		},
+	})
+}
@@ -1,10 +1,10 @@
 package hexonet
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
-	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
 	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -162,7 +162,7 @@ type Contact struct {
 	ExtraAttributes types.Map `tfsdk:"extra_attributes"`
 }
 
-func makeContactCommand(cl *apiclient.APIClient, cmd utils.CommandType, contact *Contact, oldContact *Contact, diags *diag.Diagnostics) *response.Response {
+func makeContactCommand(ctx context.Context, cl Client, cmd utils.CommandType, contact *Contact, oldContact *Contact, diags *diag.Diagnostics) *response.Response {
 	req := map[string]interface{}{
 		"COMMAND": fmt.Sprintf("%sContact", cmd),
 	}
@@ -235,11 +235,13 @@ func makeContactCommand(cl *apiclient.APIClient, cmd utils.CommandType, contact
 		return nil
 	}
 
-	return cl.Request(req)
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	return resp
 }
 
-func kindContactRead(contact *Contact, cl *apiclient.APIClient, diags *diag.Diagnostics) *Contact {
-	resp := makeContactCommand(cl, utils.CommandRead, contact, contact, diags)
+func kindContactRead(ctx context.Context, contact *Contact, cl Client, diags *diag.Diagnostics) *Contact {
+	resp := makeContactCommand(ctx, cl, utils.CommandRead, contact, contact, diags)
 	if diags.HasError() {
 		return &Contact{}
 	}
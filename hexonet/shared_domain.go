@@ -4,11 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
-	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
 	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
@@ -25,6 +26,12 @@ const MAX_WHOIS_BANNER = 3
 
 const MAX_CONTACTS = 3
 
+const RENEWAL_MODE_DEFAULT = "DEFAULT"
+const RENEWAL_MODE_AUTORENEW = "AUTORENEW"
+const RENEWAL_MODE_AUTOEXPIRE = "AUTOEXPIRE"
+const RENEWAL_MODE_AUTODELETE = "AUTODELETE"
+const RENEWAL_MODE_RENEWONCE = "RENEWONCE"
+
 func makeDomainResourceSchema() map[string]schema.Attribute {
 	res := map[string]schema.Attribute{
 		"domain": schema.StringAttribute{
@@ -137,6 +144,58 @@ func makeDomainResourceSchema() map[string]schema.Attribute {
 			},
 			Description: "DNSSEC maximum key lifespan",
 		},
+		"renewal_mode": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(RENEWAL_MODE_DEFAULT, RENEWAL_MODE_AUTORENEW, RENEWAL_MODE_AUTOEXPIRE, RENEWAL_MODE_AUTODELETE, RENEWAL_MODE_RENEWONCE),
+			},
+			Description: fmt.Sprintf("What happens at the end of the registration term: %s (account default), %s, %s (lapses), %s (deleted) or %s (renews once, then %s)", RENEWAL_MODE_DEFAULT, RENEWAL_MODE_AUTORENEW, RENEWAL_MODE_AUTOEXPIRE, RENEWAL_MODE_AUTODELETE, RENEWAL_MODE_RENEWONCE, RENEWAL_MODE_AUTOEXPIRE),
+		},
+		"created_date": schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Description: "Date the domain was registered (RFC 3339)",
+		},
+		"updated_date": schema.StringAttribute{
+			Computed:    true,
+			Description: "Date the domain was last changed (RFC 3339)",
+		},
+		"registration_expiration_date": schema.StringAttribute{
+			Computed:    true,
+			Description: "Date the registration expires at the registry (RFC 3339)",
+		},
+		"paid_until_date": schema.StringAttribute{
+			Computed:    true,
+			Description: "Date the domain is paid until in the account (RFC 3339)",
+		},
+		"renewal_date": schema.StringAttribute{
+			Computed:    true,
+			Description: "Date the domain gets renewed (or expires, depending on renewal_mode) (RFC 3339)",
+		},
+		"registrar": schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Description: "Registrar the domain is registered with",
+		},
+		"repository": schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Description: "Registry repository the domain is stored in",
+		},
+		"transfer_lock": schema.BoolAttribute{
+			Computed:    true,
+			Description: "Whether the domain is locked against transfers to other registrars",
+		},
 		"extra_attributes": schema.MapAttribute{
 			ElementType: types.StringType,
 			Optional:    true,
@@ -161,8 +220,18 @@ type Domain struct {
 	TechContacts    types.Set `tfsdk:"tech_contacts"`
 	BillingContacts types.Set `tfsdk:"billing_contacts"`
 
-	Status   types.Set    `tfsdk:"status"`
-	AuthCode types.String `tfsdk:"auth_code"`
+	Status      types.Set    `tfsdk:"status"`
+	AuthCode    types.String `tfsdk:"auth_code"`
+	RenewalMode types.String `tfsdk:"renewal_mode"`
+
+	CreatedDate                types.String `tfsdk:"created_date"`
+	UpdatedDate                types.String `tfsdk:"updated_date"`
+	RegistrationExpirationDate types.String `tfsdk:"registration_expiration_date"`
+	PaidUntilDate              types.String `tfsdk:"paid_until_date"`
+	RenewalDate                types.String `tfsdk:"renewal_date"`
+	Registrar                  types.String `tfsdk:"registrar"`
+	Repository                 types.String `tfsdk:"repository"`
+	TransferLock               types.Bool   `tfsdk:"transfer_lock"`
 
 	ExtraAttributes types.Map `tfsdk:"extra_attributes"`
 
@@ -171,7 +240,12 @@ type Domain struct {
 	DNSSECMaxSigLifespan types.Int64 `tfsdk:"dnssec_max_sig_lifespan"`
 }
 
-func makeDomainCommand(ctx context.Context, cl *apiclient.APIClient, cmd utils.CommandType, domain *Domain, oldDomain *Domain, diags *diag.Diagnostics) *response.Response {
+func makeDomainCommand(ctx context.Context, cl Client, cmd utils.CommandType, domain *Domain, oldDomain *Domain, diags *diag.Diagnostics) *response.Response {
+	return makeDomainCommandWithParams(ctx, cl, cmd, domain, oldDomain, nil, diags)
+}
+
+// Like makeDomainCommand, with additional parameters only the command itself needs (example: PERIOD for AddDomain)
+func makeDomainCommandWithParams(ctx context.Context, cl Client, cmd utils.CommandType, domain *Domain, oldDomain *Domain, params map[string]interface{}, diags *diag.Diagnostics) *response.Response {
 	if domain.Domain.IsNull() || domain.Domain.IsUnknown() {
 		diags.AddError("Main ID attribute unknwon or null", "domain is null or unknown")
 		return nil
@@ -181,6 +255,9 @@ func makeDomainCommand(ctx context.Context, cl *apiclient.APIClient, cmd utils.C
 		"COMMAND": fmt.Sprintf("%sDomain", cmd),
 		"DOMAIN":  domain.Domain.ValueString(),
 	}
+	for k, v := range params {
+		req[k] = v
+	}
 
 	if cmd == utils.CommandCreate || cmd == utils.CommandUpdate {
 		utils.FillRequestArray(ctx, domain.Status, oldDomain.Status, "STATUS", req, diags)
@@ -195,10 +272,10 @@ func makeDomainCommand(ctx context.Context, cl *apiclient.APIClient, cmd utils.C
 		utils.FillRequestArray(ctx, domain.DNSSECDSRecords, oldDomain.DNSSECDSRecords, "SECDNS-DS", req, diags)
 		utils.FillRequestArray(ctx, domain.DNSSECDnsKeyRecords, oldDomain.DNSSECDnsKeyRecords, "SECDNS-KEY", req, diags)
 
-		if !domain.DNSSECMaxSigLifespan.IsUnknown() && !domain.DNSSECMaxSigLifespan.IsNull() {
-			req["SECDNS-MAXSIGLIFE"] = fmt.Sprintf("%d", domain.DNSSECMaxSigLifespan.ValueInt64())
-		} else {
+		if domain.DNSSECMaxSigLifespan.IsNull() {
 			req["SECDNS-MAXSIGLIFE"] = "0"
+		} else if !domain.DNSSECMaxSigLifespan.IsUnknown() {
+			req["SECDNS-MAXSIGLIFE"] = fmt.Sprintf("%d", domain.DNSSECMaxSigLifespan.ValueInt64())
 		}
 
 		req["INTERNALDNS"] = "0" // Never create any resource we did not explicitly request
@@ -210,12 +287,73 @@ func makeDomainCommand(ctx context.Context, cl *apiclient.APIClient, cmd utils.C
 		return nil
 	}
 
-	resp := cl.Request(req)
-	utils.HandlePossibleErrorResponse(resp, diags)
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	return resp
+}
+
+func makeDomainRenewalModeCommand(ctx context.Context, cl Client, domain *Domain, renewalMode string, diags *diag.Diagnostics) *response.Response {
+	if domain.Domain.IsNull() || domain.Domain.IsUnknown() {
+		diags.AddError("Main ID attribute unknwon or null", "domain is null or unknown")
+		return nil
+	}
+
+	req := map[string]interface{}{
+		"COMMAND":     "SetDomainRenewalMode",
+		"DOMAIN":      domain.Domain.ValueString(),
+		"RENEWALMODE": renewalMode,
+	}
+
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	return resp
+}
+
+// Sets the renewal mode if it was configured and differs from the one in oldDomain
+func makeDomainRenewalModeUpdate(ctx context.Context, cl Client, domain *Domain, oldDomain *Domain, diags *diag.Diagnostics) {
+	if domain.RenewalMode.IsNull() || domain.RenewalMode.IsUnknown() || domain.RenewalMode.Equal(oldDomain.RenewalMode) {
+		return
+	}
+	_ = makeDomainRenewalModeCommand(ctx, cl, domain, domain.RenewalMode.ValueString(), diags)
+}
+
+// Renews the domain by period years, the API refuses it unless the domain still expires in the year of expiration
+func makeDomainRenewCommand(ctx context.Context, cl Client, domain *Domain, period int, expiration time.Time, diags *diag.Diagnostics) *response.Response {
+	if domain.Domain.IsNull() || domain.Domain.IsUnknown() {
+		diags.AddError("Main ID attribute unknwon or null", "domain is null or unknown")
+		return nil
+	}
+
+	req := map[string]interface{}{
+		"COMMAND":    "RenewDomain",
+		"DOMAIN":     domain.Domain.ValueString(),
+		"PERIOD":     fmt.Sprintf("%d", period),
+		"EXPIRATION": fmt.Sprintf("%d", expiration.Year()),
+	}
+
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
+	return resp
+}
+
+// Hands the domain back to the registry (or its transit account), it leaves the account afterwards
+func makeDomainPushCommand(ctx context.Context, cl Client, domain *Domain, diags *diag.Diagnostics) *response.Response {
+	if domain.Domain.IsNull() || domain.Domain.IsUnknown() {
+		diags.AddError("Main ID attribute unknwon or null", "domain is null or unknown")
+		return nil
+	}
+
+	req := map[string]interface{}{
+		"COMMAND": "PushDomain",
+		"DOMAIN":  domain.Domain.ValueString(),
+	}
+
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
 	return resp
 }
 
-func kindDomainRead(ctx context.Context, domain *Domain, cl *apiclient.APIClient, diags *diag.Diagnostics) *Domain {
+func kindDomainRead(ctx context.Context, domain *Domain, cl Client, diags *diag.Diagnostics) *Domain {
 	resp := makeDomainCommand(ctx, cl, utils.CommandRead, domain, domain, diags)
 	if diags.HasError() {
 		return &Domain{}
@@ -249,7 +387,17 @@ func kindDomainRead(ctx context.Context, domain *Domain, cl *apiclient.APIClient
 			types.StringType,
 			utils.StringListToAttrList(utils.ColumnOrDefault(resp, "STATUS", []string{})),
 		),
-		AuthCode: types.StringValue(utils.ColumnFirstOrDefault(resp, "AUTH", "").(string)),
+		AuthCode:    types.StringValue(utils.ColumnFirstOrDefault(resp, "AUTH", "").(string)),
+		RenewalMode: types.StringValue(utils.ColumnFirstOrDefault(resp, "RENEWALMODE", RENEWAL_MODE_DEFAULT).(string)),
+
+		CreatedDate:                utils.AutoBoxDateTime(utils.ColumnFirstOrDefault(resp, "CREATEDDATE", nil)),
+		UpdatedDate:                utils.AutoBoxDateTime(utils.ColumnFirstOrDefault(resp, "UPDATEDDATE", nil)),
+		RegistrationExpirationDate: utils.AutoBoxDateTime(utils.ColumnFirstOrDefault(resp, "REGISTRATIONEXPIRATIONDATE", nil)),
+		PaidUntilDate:              utils.AutoBoxDateTime(utils.ColumnFirstOrDefault(resp, "PAIDUNTILDATE", nil)),
+		RenewalDate:                utils.AutoBoxDateTime(utils.ColumnFirstOrDefault(resp, "RENEWALDATE", nil)),
+		Registrar:                  utils.AutoBoxString(utils.ColumnFirstOrDefault(resp, "REGISTRAR", nil)),
+		Repository:                 utils.AutoBoxString(utils.ColumnFirstOrDefault(resp, "REPOSITORY", nil)),
+		TransferLock:               utils.AutoBoxBoolNumberStr(utils.ColumnFirstOrDefault(resp, "TRANSFERLOCK", nil)),
 
 		OwnerContacts: types.SetValueMust(
 			types.StringType,
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet/utils"
-	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/apiclient"
 	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -49,7 +48,7 @@ type NameServer struct {
 	IpAddresses types.List   `tfsdk:"ip_addresses"`
 }
 
-func makeNameServerCommand(ctx context.Context, cl *apiclient.APIClient, cmd utils.CommandType, ns *NameServer, oldNs *NameServer, diags *diag.Diagnostics) *response.Response {
+func makeNameServerCommand(ctx context.Context, cl Client, cmd utils.CommandType, ns *NameServer, oldNs *NameServer, diags *diag.Diagnostics) *response.Response {
 	if ns.Host.IsNull() || ns.Host.IsUnknown() {
 		diags.AddError("Main ID attribute unknwon or null", "host is null or unknown")
 		return nil
@@ -69,12 +68,12 @@ func makeNameServerCommand(ctx context.Context, cl *apiclient.APIClient, cmd uti
 		return nil
 	}
 
-	resp := cl.Request(req)
-	utils.HandlePossibleErrorResponse(resp, diags)
+	resp := cl.Request(ctx, req)
+	utils.HandlePossibleErrorResponse(ctx, resp, diags)
 	return resp
 }
 
-func kindNameserverRead(ctx context.Context, ns *NameServer, cl *apiclient.APIClient, diags *diag.Diagnostics) *NameServer {
+func kindNameserverRead(ctx context.Context, ns *NameServer, cl Client, diags *diag.Diagnostics) *NameServer {
 	resp := makeNameServerCommand(ctx, cl, utils.CommandRead, ns, ns, diags)
 	if diags.HasError() {
 		return &NameServer{}
@@ -59,7 +59,12 @@ func FillRequestArray(ctx context.Context, list elementsAsCapableValue, oldList
 }
 
 func FillRequestArrayWithIgnore(ctx context.Context, listObj elementsAsCapableValue, oldListObj elementsAsCapableValue, prefix string, req map[string]interface{}, diags *diag.Diagnostics, ignore map[string]bool) {
-	if listObj.IsUnknown() || oldListObj.IsUnknown() {
+	// Unknown means the value is computed by the API, so leave whatever is there untouched
+	if listObj.IsUnknown() {
+		return
+	}
+
+	if oldListObj.IsUnknown() {
 		HandleUnexpectedUnknown(diags)
 		return
 	}
@@ -143,6 +148,11 @@ func extraAttributeName(name string) string {
 }
 
 func HandleExtraAttributesWrite(extraAttributesBox types.Map, oldExtraAttributesBox types.Map, req map[string]interface{}) {
+	// Unknown means the value is computed by the API, so leave whatever is there untouched
+	if extraAttributesBox.IsUnknown() {
+		return
+	}
+
 	// Get all the previous attributes and set them to empty string (remove)
 	// That way, if they are not in the current config, this will clear them correctly
 	hasOldAttributes := !oldExtraAttributesBox.IsNull() && !oldExtraAttributesBox.IsUnknown()
@@ -1,27 +1,165 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 )
 
+// Error diagnostic which retains the classified API error, so callers can react to specific errors
+type ResponseErrorDiagnostic struct {
+	Err     error
+	summary string
+	detail  string
+}
+
+var _ diag.Diagnostic = ResponseErrorDiagnostic{}
+
+func (d ResponseErrorDiagnostic) Severity() diag.Severity {
+	return diag.SeverityError
+}
+
+func (d ResponseErrorDiagnostic) Summary() string {
+	return d.summary
+}
+
+func (d ResponseErrorDiagnostic) Detail() string {
+	return d.detail
+}
+
+func (d ResponseErrorDiagnostic) Equal(other diag.Diagnostic) bool {
+	o, ok := other.(ResponseErrorDiagnostic)
+	if !ok {
+		return false
+	}
+	return o.summary == d.summary && o.detail == d.detail
+}
+
 func MakeNotConfiguredError(diags *diag.Diagnostics) {
 	diags.AddError("Provider not configured", "Please make sure the provider is configured correctly")
 }
 
-func HandlePossibleErrorResponse(resp *response.Response, diags *diag.Diagnostics) {
-	if !resp.IsError() {
+func HandlePossibleErrorResponse(ctx context.Context, resp *response.Response, diags *diag.Diagnostics) {
+	// A command which went through just before the deadline (or cancellation) still succeeded
+	err := ClassifyResponse(resp)
+	if err == nil {
+		return
+	}
+
+	// Real API errors are reported as they are, even if the context has ended meanwhile
+	switch LocalResponseReasonOf(resp) {
+	case LocalResponseCancelled:
+		HandleContextDone(ctx, diags)
+		return
+	case LocalResponseAbandoned:
+		diags.AddError(
+			contextDoneSummary(ctx),
+			fmt.Sprintf("The provider stopped waiting for the API, but the command was already sent and may still complete, check the object before retrying\n\nCommand sent:\n%s", commandPlainMasked(resp)),
+		)
+		return
+	}
+
+	var respErr *ResponseError
+	errors.As(err, &respErr)
+
+	commandLabel := "Command sent"
+	if respErr.Category == ErrorCategoryReadOnly {
+		commandLabel = "Command that would have been sent"
+	}
+
+	diags.Append(ResponseErrorDiagnostic{
+		Err:     err,
+		summary: fmt.Sprintf("%s (error %d in %s)", respErr.Category, respErr.Code, respErr.Command),
+		detail:  fmt.Sprintf("%s\n\n%s\n\n%s:\n%s", respErr.Description, respErr.Category.Hint(), commandLabel, commandPlainMasked(resp)),
+	})
+}
+
+// Command parameters which must not show up in diagnostics
+var sensitiveCommandParams = map[string]bool{
+	"AUTH":     true,
+	"PASSWORD": true,
+}
+
+// Like resp.GetCommandPlain, but with sensitive parameters masked
+func commandPlainMasked(resp *response.Response) string {
+	cmd := resp.GetCommand()
+	keys := make([]string, 0, len(cmd))
+	for k := range cmd {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var res strings.Builder
+	for _, k := range keys {
+		if k == LocalResponseParam {
+			continue
+		}
+		val := cmd[k]
+		if sensitiveCommandParams[strings.ToUpper(k)] {
+			val = "***"
+		}
+		res.WriteString(fmt.Sprintf("%s = %s\n", k, val))
+	}
+	return res.String()
+}
+
+// Returns the first classified API error contained in the diagnostics (or nil), use errors.As to check for specific types
+func ErrorFromDiagnostics(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		respDiag, ok := d.(ResponseErrorDiagnostic)
+		if ok {
+			return respDiag.Err
+		}
+	}
+	return nil
+}
+
+// Whether the diagnostics contain an error caused by the requested object not existing
+func IsNotFound(diags diag.Diagnostics) bool {
+	var notFoundErr *NotFoundError
+	return errors.As(ErrorFromDiagnostics(diags), &notFoundErr)
+}
+
+// Appends the diagnostics of reading a resource, unless the object was removed outside of Terraform
+// In that case the resource is removed from the state instead, so Terraform plans to recreate it
+// Returns whether Read has to stop there
+func RemoveIfNotFound(ctx context.Context, readDiags diag.Diagnostics, state *tfsdk.State, diags *diag.Diagnostics) bool {
+	if IsNotFound(readDiags) {
+		state.RemoveResource(ctx)
+		return true
+	}
+	diags.Append(readDiags...)
+	return diags.HasError()
+}
+
+func HandleContextDone(ctx context.Context, diags *diag.Diagnostics) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		diags.AddError(
+			contextDoneSummary(ctx),
+			"The operation did not finish in time and was aborted, consider raising the timeout",
+		)
 		return
 	}
 
 	diags.AddError(
-		fmt.Sprintf("Error %d in %s", resp.GetCode(), resp.GetCommandPlain()),
-		resp.GetDescription(),
+		contextDoneSummary(ctx),
+		"The operation was cancelled before it finished, the object might have been changed partially",
 	)
 }
 
+func contextDoneSummary(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "Operation timed out"
+	}
+	return "Operation cancelled"
+}
+
 func HandleUnexpectedUnknown(diags *diag.Diagnostics) {
 	diags.AddError(
 		"Encountered Unknown value in list",
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/responsetemplatemanager"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func makeTestResponse(code string, description string) *response.Response {
+	tpl := responsetemplatemanager.GetInstance().GenerateTemplate(code, description)
+	return response.NewResponse(tpl, map[string]string{"COMMAND": "AddDomain"})
+}
+
+func makeLocalTestResponse(reason LocalResponseReason) *response.Response {
+	resp := makeTestResponse("421", "Command cancelled: context canceled")
+	resp.GetCommand()[LocalResponseParam] = string(reason)
+	return resp
+}
+
+func TestHandlePossibleErrorResponse(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		resp    *response.Response
+		summary string
+	}{
+		{"success", context.Background(), makeTestResponse("200", "Command completed successfully"), ""},
+		// The command went through, so its result must not be dropped because the context ended meanwhile
+		{"success after cancel", cancelled, makeTestResponse("200", "Command completed successfully"), ""},
+		{"failure", context.Background(), makeTestResponse("545", "Object does not exist"), "Object not found (error 545 in AddDomain)"},
+		// Real API errors must not be hidden behind the cancellation
+		{"failure after cancel", cancelled, makeTestResponse("545", "Object does not exist"), "Object not found (error 545 in AddDomain)"},
+		{"cancelled", cancelled, makeLocalTestResponse(LocalResponseCancelled), "Operation cancelled"},
+		{"abandoned", cancelled, makeLocalTestResponse(LocalResponseAbandoned), "Operation cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := diag.Diagnostics{}
+			HandlePossibleErrorResponse(tt.ctx, tt.resp, &diags)
+			if tt.summary == "" {
+				if diags.HasError() {
+					t.Fatalf("expected no error, got %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Summary() != tt.summary {
+				t.Fatalf("expected error %q, got %v", tt.summary, diags)
+			}
+		})
+	}
+}
+
+func TestClassifyResponseReadOnly(t *testing.T) {
+	// Only the provider's own responses are read-only refusals, whatever the API writes into its descriptions
+	var readOnlyErr *ReadOnlyError
+	if err := ClassifyResponse(makeTestResponse("549", "Command blocked by read-only mode")); errors.As(err, &readOnlyErr) {
+		t.Errorf("expected an API response not to be classified as read-only, got %v", err)
+	}
+
+	resp := makeTestResponse("549", "Command blocked")
+	resp.GetCommand()[LocalResponseParam] = string(LocalResponseReadOnly)
+	if err := ClassifyResponse(resp); !errors.As(err, &readOnlyErr) {
+		t.Errorf("expected a read-only error, got %v", err)
+	}
+}
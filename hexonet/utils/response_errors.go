@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/centralnicgroup-opensource/rtldev-middleware-go-sdk/v3/response"
+)
+
+// Categories of failed API responses
+type ErrorCategory string
+
+const (
+	ErrorCategoryUnknown          ErrorCategory = "Unknown error"
+	ErrorCategoryNotFound         ErrorCategory = "Object not found"
+	ErrorCategoryAuthentication   ErrorCategory = "Authentication failed"
+	ErrorCategoryPermissionDenied ErrorCategory = "Permission denied"
+	ErrorCategoryObjectExists     ErrorCategory = "Object already exists"
+	ErrorCategoryTemporary        ErrorCategory = "Temporary failure"
+	ErrorCategoryRateLimited      ErrorCategory = "Rate limited"
+	ErrorCategoryPending          ErrorCategory = "Pending operation"
+	ErrorCategoryInvalidParameter ErrorCategory = "Invalid parameter"
+	ErrorCategoryReadOnly         ErrorCategory = "Blocked by read-only mode"
+)
+
+// Response codes with special meaning to the provider
+const (
+	CodeInvalidCommand        = 500
+	CodeInvalidAttributeName  = 503
+	CodeMissingAttribute      = 504
+	CodeInvalidAttributeValue = 505
+	CodeAuthenticationFailed  = 530
+	CodeAuthorizationFailed   = 531
+	CodeObjectExists          = 540
+	CodeInvalidValue          = 541
+	CodeObjectNotFound        = 545
+	CodeCommandFailed         = 549
+)
+
+// Command parameter marking responses the provider generates itself instead of the API, it is never sent
+const LocalResponseParam = "X-PROVIDER-LOCAL-RESPONSE"
+
+// Reasons for responses generated by the provider
+type LocalResponseReason string
+
+const (
+	// The context ended before the command was sent, or while the provider was sending it
+	LocalResponseCancelled LocalResponseReason = "CANCELLED"
+	// The context ended while the SDK was still sending the command, which may still complete
+	LocalResponseAbandoned LocalResponseReason = "ABANDONED"
+	// The command was not sent as it could change objects and the provider is configured with read_only
+	LocalResponseReadOnly LocalResponseReason = "READ_ONLY"
+)
+
+// Returns why the provider generated the response, or "" for responses of the API
+func LocalResponseReasonOf(resp *response.Response) LocalResponseReason {
+	return LocalResponseReason(resp.GetCommand()[LocalResponseParam])
+}
+
+var errorCategoryHints = map[ErrorCategory]string{
+	ErrorCategoryUnknown:          "Check the response description and the Hexonet API documentation for this command",
+	ErrorCategoryNotFound:         "Make sure the object exists in the account (and system, live or OT&E) the provider is configured for",
+	ErrorCategoryAuthentication:   "Check username, role, password and MFA settings of the provider, and whether the session has expired",
+	ErrorCategoryPermissionDenied: "Make sure the account or role is allowed to run this command and owns the object",
+	ErrorCategoryObjectExists:     "The object already exists, import it into the Terraform state instead of creating it",
+	ErrorCategoryTemporary:        "This is usually transient, retry the operation later",
+	ErrorCategoryRateLimited:      "Too many requests were sent, wait a bit and retry with fewer parallel operations (-parallelism)",
+	ErrorCategoryPending:          "Another operation on this object is still pending, wait for it to finish and retry",
+	ErrorCategoryInvalidParameter: "Check the configured values against the Hexonet API documentation for this command",
+	ErrorCategoryReadOnly:         "The provider is configured with read_only, so no changes are sent to the API",
+}
+
+func (c ErrorCategory) Hint() string {
+	return errorCategoryHints[c]
+}
+
+// Generic error for a failed API response, wrapped by the typed errors below
+type ResponseError struct {
+	Code        int
+	Description string
+	Command     string
+	Category    ErrorCategory
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("error %d in %s: %s", e.Code, e.Command, e.Description)
+}
+
+type NotFoundError struct{ *ResponseError }
+type AuthenticationError struct{ *ResponseError }
+type PermissionDeniedError struct{ *ResponseError }
+type ObjectExistsError struct{ *ResponseError }
+type TemporaryError struct{ *ResponseError }
+type RateLimitedError struct{ *ResponseError }
+type PendingOperationError struct{ *ResponseError }
+type InvalidParameterError struct{ *ResponseError }
+type ReadOnlyError struct{ *ResponseError }
+
+func (e *NotFoundError) Unwrap() error         { return e.ResponseError }
+func (e *AuthenticationError) Unwrap() error   { return e.ResponseError }
+func (e *PermissionDeniedError) Unwrap() error { return e.ResponseError }
+func (e *ObjectExistsError) Unwrap() error     { return e.ResponseError }
+func (e *TemporaryError) Unwrap() error        { return e.ResponseError }
+func (e *RateLimitedError) Unwrap() error      { return e.ResponseError }
+func (e *PendingOperationError) Unwrap() error { return e.ResponseError }
+func (e *InvalidParameterError) Unwrap() error { return e.ResponseError }
+func (e *ReadOnlyError) Unwrap() error         { return e.ResponseError }
+
+func ClassifyResponseCode(code int, description string) ErrorCategory {
+	desc := strings.ToLower(description)
+
+	switch {
+	case code >= 200 && code <= 299:
+		return ""
+	case strings.Contains(desc, "rate limit") || strings.Contains(desc, "too many"):
+		return ErrorCategoryRateLimited
+	case code >= 400 && code <= 499:
+		return ErrorCategoryTemporary
+	case strings.Contains(desc, "pending"):
+		return ErrorCategoryPending
+	}
+
+	switch code {
+	case CodeObjectNotFound:
+		return ErrorCategoryNotFound
+	case CodeAuthenticationFailed:
+		return ErrorCategoryAuthentication
+	case CodeAuthorizationFailed:
+		return ErrorCategoryPermissionDenied
+	case CodeObjectExists:
+		return ErrorCategoryObjectExists
+	case CodeInvalidCommand, CodeInvalidAttributeName, CodeMissingAttribute, CodeInvalidAttributeValue, CodeInvalidValue:
+		return ErrorCategoryInvalidParameter
+	}
+
+	return ErrorCategoryUnknown
+}
+
+// Turns a failed API response into one of the typed errors above, returns nil for successful responses
+func ClassifyResponse(resp *response.Response) error {
+	category := ClassifyResponseCode(resp.GetCode(), resp.GetDescription())
+	if LocalResponseReasonOf(resp) == LocalResponseReadOnly {
+		category = ErrorCategoryReadOnly
+	}
+	if category == "" {
+		return nil
+	}
+
+	base := &ResponseError{
+		Code:        resp.GetCode(),
+		Description: resp.GetDescription(),
+		Command:     resp.GetCommand()["COMMAND"],
+		Category:    category,
+	}
+
+	switch category {
+	case ErrorCategoryNotFound:
+		return &NotFoundError{base}
+	case ErrorCategoryAuthentication:
+		return &AuthenticationError{base}
+	case ErrorCategoryPermissionDenied:
+		return &PermissionDeniedError{base}
+	case ErrorCategoryObjectExists:
+		return &ObjectExistsError{base}
+	case ErrorCategoryTemporary:
+		return &TemporaryError{base}
+	case ErrorCategoryRateLimited:
+		return &RateLimitedError{base}
+	case ErrorCategoryPending:
+		return &PendingOperationError{base}
+	case ErrorCategoryInvalidParameter:
+		return &InvalidParameterError{base}
+	case ErrorCategoryReadOnly:
+		return &ReadOnlyError{base}
+	}
+	return base
+}
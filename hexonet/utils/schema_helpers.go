@@ -7,6 +7,17 @@ import (
 	resource_schema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
+// Combines multiple attribute maps into one, later maps win on duplicate names
+func MergeResourceSchemas(schemas ...map[string]resource_schema.Attribute) map[string]resource_schema.Attribute {
+	res := make(map[string]resource_schema.Attribute)
+	for _, schema := range schemas {
+		for name, attr := range schema {
+			res[name] = attr
+		}
+	}
+	return res
+}
+
 func ResourceSchemaToDataSourceSchema(resourceSchema map[string]resource_schema.Attribute, idField string) map[string]datasource_schema.Attribute {
 	foundIdField := false
 
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TOTP parameters used by Hexonet (and basically every authenticator app)
+const TOTP_PERIOD = 30 * time.Second
+const TOTP_DIGITS = 6
+
+// GenerateTOTP computes the RFC 6238 code for the given base32 secret at the given time
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	// Authenticator apps usually show the secret in lowercase groups without padding, accept that as well
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret, expected base32: %w", err)
+	}
+	if len(key) == 0 {
+		return "", fmt.Errorf("invalid TOTP secret, must not be empty")
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/int64(TOTP_PERIOD/time.Second)))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation as per RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTP_DIGITS; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTP_DIGITS, code%mod), nil
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// The RFC 6238 secret "12345678901234567890" in base32
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTP(t *testing.T) {
+	// The SHA-1 test vectors of RFC 6238 appendix B, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := GenerateTOTP(testTOTPSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("T=%d: %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("T=%d: expected %s, got %s", tt.unix, tt.code, code)
+		}
+	}
+}
+
+func TestGenerateTOTPSecretFormats(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, secret := range []string{
+		"gezdgnbvgy3tqojqgezdgnbvgy3tqojq",
+		"GEZD GNBV GY3T QOJQ GEZD GNBV GY3T QOJQ",
+		"gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+	} {
+		code, err := GenerateTOTP(secret, now)
+		if err != nil {
+			t.Fatalf("%q: %v", secret, err)
+		}
+		if code != "287082" {
+			t.Errorf("%q: expected 287082, got %s", secret, code)
+		}
+	}
+
+	// The secret "a" needs padding, which may be given or left out
+	padded, err := GenerateTOTP("ME======", now)
+	if err != nil {
+		t.Fatalf("padded secret: %v", err)
+	}
+	unpadded, err := GenerateTOTP("me", now)
+	if err != nil {
+		t.Fatalf("unpadded secret: %v", err)
+	}
+	if padded != unpadded {
+		t.Errorf("padded and unpadded secret differ: %s != %s", padded, unpadded)
+	}
+}
+
+func TestGenerateTOTPInvalidSecret(t *testing.T) {
+	for _, secret := range []string{"", "===", "not-base32!"} {
+		if _, err := GenerateTOTP(secret, time.Unix(59, 0)); err == nil {
+			t.Errorf("%q: expected an error", secret)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -54,6 +56,23 @@ func AutoBoxBoolNumberStr(str interface{}) types.Bool {
 	return types.BoolValue(NumberStrToBool(str.(string)))
 }
 
+// Layout of timestamps returned by the API (always UTC), parsing also accepts a trailing fraction (.0)
+const API_DATETIME_LAYOUT = "2006-01-02 15:04:05"
+
+// Converts API timestamps to RFC 3339, so they work with Terraform's timecmp and timeadd functions
+// Values in any other format are kept as they are
+func AutoBoxDateTime(str interface{}) types.String {
+	if str == nil || str == "" {
+		return types.StringNull()
+	}
+
+	t, err := time.Parse(API_DATETIME_LAYOUT, str.(string))
+	if err != nil {
+		return types.StringValue(str.(string))
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339))
+}
+
 func AutoUnboxString(str types.String, def string) string {
 	if str.IsNull() || str.IsUnknown() {
 		return def
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"log"
 
 	"github.com/Doridian/terraform-provider-hexonet/hexonet"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -10,7 +11,14 @@ import (
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
 func main() {
-	providerserver.Serve(context.Background(), hexonet.New, providerserver.ServeOpts{
+	err := providerserver.Serve(context.Background(), hexonet.New, providerserver.ServeOpts{
 		Address: "registry.terraform.io/doridian/hexonet",
 	})
+
+	// Terraform stops the plugin once it is done, so this is the last chance to end the sessions
+	hexonet.LogoutAll()
+
+	if err != nil {
+		log.Fatal(err)
+	}
 }